@@ -31,13 +31,14 @@ func beforeCreateCallback(scope *Scope) {
 // updateTimeStampForCreateCallback will set `CreatedAt`, `UpdatedAt` when creating
 func updateTimeStampForCreateCallback(scope *Scope) {
 	if !scope.HasError() {
-		now := NowFunc()
+		now := scope.db.nowTime()
 		scope.SetColumn("CreatedAt", now)
 		scope.SetColumn("UpdatedAt", now)
 	}
 }
 
-// createCallback the callback used to insert data into database
+// createCallback the callback used to insert data into database, conflicting records are updated if columns to update are set with `gorm:upsert`
+//     db.Set("gorm:upsert", []string{"name", "age"}).Create(&user)
 func createCallback(scope *Scope) {
 	if !scope.HasError() {
 		defer scope.trace(NowFunc())
@@ -79,32 +80,57 @@ func createCallback(scope *Scope) {
 			extraOption = fmt.Sprint(str)
 		}
 
+		if upsertColumns, ok := scope.Get("gorm:upsert"); ok {
+			if upsertColumns, ok := upsertColumns.([]string); ok {
+				extraOption = upsertSQL(scope, upsertColumns) + addExtraSpaceIfExist(extraOption)
+			}
+		}
+
 		if primaryField != nil {
 			returningColumn = scope.Quote(primaryField.DBName)
 		}
 
-		lastInsertIDReturningSuffix := scope.Dialect().LastInsertIDReturningSuffix(quotedTableName, returningColumn)
+		var lastInsertIDReturningSuffix, lastInsertIDOutputInterstitial string
+		if scope.Dialect().Capabilities().Returning {
+			lastInsertIDReturningSuffix = scope.Dialect().LastInsertIDReturningSuffix(quotedTableName, returningColumn)
+		} else if dialect, ok := scope.Dialect().(InsertedIDDialect); ok {
+			lastInsertIDOutputInterstitial = dialect.LastInsertIDOutputInterstitial(quotedTableName, returningColumn)
+		}
 
-		if len(columns) == 0 {
+		if len(columns) == 0 && !scope.Dialect().Capabilities().DefaultValues {
 			scope.Raw(fmt.Sprintf(
-				"INSERT INTO %v DEFAULT VALUES%v%v",
+				"INSERT INTO %v ()%v VALUES ()%v%v",
 				quotedTableName,
+				addExtraSpaceIfExist(lastInsertIDOutputInterstitial),
+				addExtraSpaceIfExist(extraOption),
+				addExtraSpaceIfExist(lastInsertIDReturningSuffix),
+			))
+		} else if len(columns) == 0 {
+			scope.Raw(fmt.Sprintf(
+				"INSERT INTO %v%v DEFAULT VALUES%v%v",
+				quotedTableName,
+				addExtraSpaceIfExist(lastInsertIDOutputInterstitial),
 				addExtraSpaceIfExist(extraOption),
 				addExtraSpaceIfExist(lastInsertIDReturningSuffix),
 			))
 		} else {
 			scope.Raw(fmt.Sprintf(
-				"INSERT INTO %v (%v) VALUES (%v)%v%v",
+				"INSERT INTO %v (%v)%v VALUES (%v)%v%v",
 				scope.QuotedTableName(),
 				strings.Join(columns, ","),
+				addExtraSpaceIfExist(lastInsertIDOutputInterstitial),
 				strings.Join(placeholders, ","),
 				addExtraSpaceIfExist(extraOption),
 				addExtraSpaceIfExist(lastInsertIDReturningSuffix),
 			))
 		}
 
+		if scope.HasError() {
+			return
+		}
+
 		// execute create sql
-		if lastInsertIDReturningSuffix == "" || primaryField == nil {
+		if (lastInsertIDReturningSuffix == "" && lastInsertIDOutputInterstitial == "") || primaryField == nil {
 			if result, err := scope.SQLDB().Exec(scope.SQL, scope.SQLVars...); scope.Err(err) == nil {
 				// set rows affected count
 				scope.db.RowsAffected, _ = result.RowsAffected()
@@ -125,6 +151,37 @@ func createCallback(scope *Scope) {
 	}
 }
 
+// upsertSQL return clause to update columns of record conflicting on primary keys, with upsert style of the dialect
+func upsertSQL(scope *Scope, columns []string) string {
+	var assignments []string
+	switch scope.Dialect().Capabilities().Upsert {
+	case UpsertOnConflict:
+		var primaryKeys []string
+		for _, field := range scope.PrimaryFields() {
+			primaryKeys = append(primaryKeys, scope.Quote(field.DBName))
+		}
+
+		for _, column := range columns {
+			assignments = append(assignments, fmt.Sprintf("%v = excluded.%v", scope.Quote(column), scope.Quote(column)))
+		}
+		return fmt.Sprintf("ON CONFLICT (%v) DO UPDATE SET %v", strings.Join(primaryKeys, ","), strings.Join(assignments, ","))
+	case UpsertOnDuplicateKey:
+		for _, column := range columns {
+			assignments = append(assignments, fmt.Sprintf("%v = VALUES(%v)", scope.Quote(column), scope.Quote(column)))
+		}
+
+		// LastInsertId returns id of the updated record with it
+		if primaryField := scope.PrimaryField(); primaryField != nil {
+			quotedColumn := scope.Quote(primaryField.DBName)
+			assignments = append(assignments, fmt.Sprintf("%v = LAST_INSERT_ID(%v)", quotedColumn, quotedColumn))
+		}
+		return "ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ",")
+	}
+
+	scope.Err(fmt.Errorf("%v doesn't support upsert", scope.Dialect().GetName()))
+	return ""
+}
+
 // forceReloadAfterCreateCallback will reload columns that having default value, and set it back to current object
 func forceReloadAfterCreateCallback(scope *Scope) {
 	if blankColumnsWithDefaultValue, ok := scope.InstanceGet("gorm:blank_columns_with_default_value"); ok {
@@ -30,7 +30,7 @@ func deleteCallback(scope *Scope) {
 			scope.Raw(fmt.Sprintf(
 				"UPDATE %v SET deleted_at=%v%v%v",
 				scope.QuotedTableName(),
-				scope.AddToVars(NowFunc()),
+				scope.AddToVars(scope.db.nowTime()),
 				addExtraSpaceIfExist(scope.CombinedConditionSql()),
 				addExtraSpaceIfExist(extraOption),
 			)).Exec()
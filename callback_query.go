@@ -23,7 +23,8 @@ func queryCallback(scope *Scope) {
 		results        = scope.IndirectValue()
 	)
 
-	if orderBy, ok := scope.Get("gorm:order_by_primary_key"); ok {
+	// raw SQL couldn't be ordered by appending order clause
+	if orderBy, ok := scope.Get("gorm:order_by_primary_key"); ok && !scope.Search.raw {
 		if primaryField := scope.PrimaryField(); primaryField != nil {
 			scope.Search.Order(fmt.Sprintf("%v.%v %v", scope.QuotedTableName(), scope.Quote(primaryField.DBName), orderBy))
 		}
@@ -59,6 +60,7 @@ func queryCallback(scope *Scope) {
 			defer rows.Close()
 
 			columns, _ := rows.Columns()
+			scanner := getScanPlan(scope.New(reflect.New(results.Type()).Interface()), columns).newScanner()
 			for rows.Next() {
 				scope.db.RowsAffected++
 
@@ -67,7 +69,7 @@ func queryCallback(scope *Scope) {
 					elem = reflect.New(resultType).Elem()
 				}
 
-				scope.scan(rows, columns, scope.New(elem.Addr().Interface()).Fields())
+				scope.Err(scanner.scan(rows, elem))
 
 				if isSlice {
 					if isPtr {
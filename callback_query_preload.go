@@ -4,12 +4,14 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 // preloadCallback used to preload associations
 func preloadCallback(scope *Scope) {
-	if scope.Search.preload == nil || scope.HasError() {
+	if (scope.Search.preload == nil && scope.Search.preloadCounts == nil) || scope.HasError() {
 		return
 	}
 
@@ -18,7 +20,12 @@ func preloadCallback(scope *Scope) {
 		fields       = scope.Fields()
 	)
 
-	for _, preload := range scope.Search.preload {
+	preloads, err := scope.expandPreloadWildcards()
+	if scope.Err(err) != nil {
+		return
+	}
+
+	for _, preload := range preloads {
 		var (
 			preloadFields = strings.Split(preload.schema, ".")
 			currentScope  = scope
@@ -45,18 +52,7 @@ func preloadCallback(scope *Scope) {
 						continue
 					}
 
-					switch field.Relationship.Kind {
-					case "has_one":
-						currentScope.handleHasOnePreload(field, currentPreloadConditions)
-					case "has_many":
-						currentScope.handleHasManyPreload(field, currentPreloadConditions)
-					case "belongs_to":
-						currentScope.handleBelongsToPreload(field, currentPreloadConditions)
-					case "many_to_many":
-						currentScope.handleManyToManyPreload(field, currentPreloadConditions)
-					default:
-						scope.Err(errors.New("unsupported relation"))
-					}
+					currentScope.handlePreloadInChunks(field, currentPreloadConditions)
 
 					preloadedMap[preloadKey] = true
 					break
@@ -77,6 +73,405 @@ func preloadCallback(scope *Scope) {
 			}
 		}
 	}
+
+	for _, preload := range scope.Search.preloadCounts {
+		scope.inPreloadChunks(preload.schema, func(chunkScope *Scope) {
+			chunkScope.handlePreloadCount(preload.schema, preload.conditions)
+		})
+	}
+}
+
+// preloadReservedBindVars placeholders reserved for preload conditions when splitting parents into chunks
+const preloadReservedBindVars = 100
+
+// handlePreloadInChunks split parents into chunks so keys in `IN` conditions don't exceed dialect's `Capabilities().MaxBindVars`,
+// chunk size could be changed with setting `gorm:preload_chunk_size`, chunks are preloaded in parallel if `gorm:preload_parallel` is set,
+// except in transactions, whose only connection can't run queries concurrently
+func (scope *Scope) handlePreloadInChunks(field *Field, conditions []interface{}) {
+	scope.inPreloadChunks(field.Name, func(chunkScope *Scope) {
+		chunkScope.handlePreload(field, conditions)
+	})
+}
+
+func (scope *Scope) inPreloadChunks(relationName string, fc func(chunkScope *Scope)) {
+	var (
+		indirectScopeValue = scope.IndirectValue()
+		chunkSize          int
+	)
+
+	if field, ok := scope.GetModelStruct().relationField(relationName); ok {
+		chunkSize = scope.preloadChunkSize(field.Relationship)
+	}
+
+	if indirectScopeValue.Kind() != reflect.Slice || chunkSize <= 0 || indirectScopeValue.Len() <= chunkSize {
+		fc(scope)
+		return
+	}
+
+	var (
+		parallel, _      = scope.Get("gorm:preload_parallel")
+		_, inTransaction = scope.db.db.(sqlTx)
+		chunkScopes      []*Scope
+		wg               sync.WaitGroup
+	)
+
+	for start := 0; start < indirectScopeValue.Len(); start += chunkSize {
+		end := start + chunkSize
+		if end > indirectScopeValue.Len() {
+			end = indirectScopeValue.Len()
+		}
+
+		// chunks share elements with parents, so preloaded associations are assigned to parents directly
+		chunk := reflect.New(indirectScopeValue.Type())
+		chunk.Elem().Set(indirectScopeValue.Slice(start, end))
+		chunkScope := &Scope{db: scope.NewDB(), Search: scope.Search.clone(), Value: chunk.Interface()}
+		chunkScope.Search.db = chunkScope.db
+		chunkScopes = append(chunkScopes, chunkScope)
+
+		if parallel == true && !inTransaction {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fc(chunkScope)
+			}()
+		} else {
+			fc(chunkScope)
+		}
+	}
+	wg.Wait()
+
+	for _, chunkScope := range chunkScopes {
+		scope.Err(chunkScope.db.Error)
+	}
+}
+
+func (scope *Scope) preloadChunkSize(relation *Relationship) int {
+	if size, ok := scope.Get("gorm:preload_chunk_size"); ok {
+		if size, ok := size.(int); ok && size > 0 {
+			return size
+		}
+	}
+
+	maxBindVars := scope.Dialect().Capabilities().MaxBindVars
+	if maxBindVars <= 0 {
+		return 0
+	}
+
+	keysCount := len(relation.ForeignDBNames)
+	if keysCount == 0 {
+		keysCount = 1
+	}
+
+	if chunkSize := (maxBindVars - preloadReservedBindVars) / keysCount; chunkSize > 0 {
+		return chunkSize
+	}
+	return 1
+}
+
+func (scope *Scope) handlePreload(field *Field, conditions []interface{}) {
+	switch field.Relationship.Kind {
+	case "has_one":
+		scope.handleHasOnePreload(field, conditions)
+	case "has_many":
+		scope.handleHasManyPreload(field, conditions)
+	case "belongs_to":
+		scope.handleBelongsToPreload(field, conditions)
+	case "many_to_many":
+		scope.handleManyToManyPreload(field, conditions)
+	default:
+		scope.Err(errors.New("unsupported relation"))
+	}
+}
+
+// handlePreloadCount count has one, has many or many to many associations of parents with one `GROUP BY` query,
+// counts are assigned to field tagged with `count:<relation>`
+func (scope *Scope) handlePreloadCount(name string, conditions []interface{}) {
+	var relationField, countField *StructField
+	for _, field := range scope.GetModelStruct().StructFields {
+		if field.Name == name && field.Relationship != nil {
+			relationField = field
+		}
+		if count, ok := field.TagSettings["COUNT"]; ok && count == name {
+			countField = field
+		}
+	}
+
+	if relationField == nil {
+		scope.Err(fmt.Errorf("can't preload count of field %s for %s", name, scope.GetModelStruct().ModelType))
+		return
+	}
+
+	if countField == nil {
+		scope.Err(fmt.Errorf("no field tagged with count:%s for %s", name, scope.GetModelStruct().ModelType))
+		return
+	}
+
+	var (
+		relation                     = relationField.Relationship
+		relatedValue                 = reflect.New(scope.relationModelStruct(relationField).ModelType).Interface()
+		relatedTableName             = scope.New(relatedValue).QuotedTableName()
+		preloadDB, preloadConditions = scope.generatePreloadDBWithConditions(conditions)
+		keyColumns, parentFieldNames []string
+	)
+	preloadDB = preloadDB.Model(relatedValue)
+
+	switch relation.Kind {
+	case "has_one", "has_many":
+		for _, dbName := range relation.ForeignDBNames {
+			keyColumns = append(keyColumns, fmt.Sprintf("%v.%v", relatedTableName, scope.Quote(dbName)))
+		}
+		parentFieldNames = relation.AssociationForeignFieldNames
+
+		if relation.PolymorphicType != "" {
+			preloadDB = preloadDB.Where(fmt.Sprintf("%v.%v = ?", relatedTableName, scope.Quote(relation.PolymorphicDBName)), scope.TableName())
+		}
+	case "many_to_many":
+		var (
+			joinTableName  = relation.JoinTableHandler.Table(scope.db)
+			joinConditions []string
+		)
+
+		for _, key := range relation.JoinTableHandler.DestinationForeignKeys() {
+			joinConditions = append(joinConditions, fmt.Sprintf("%v = %v.%v", scope.Quote(joinTableName+"."+key.DBName), relatedTableName, scope.Quote(key.AssociationDBName)))
+		}
+		preloadDB = preloadDB.Joins(fmt.Sprintf("INNER JOIN %v ON %v", scope.Quote(joinTableName), strings.Join(joinConditions, " AND ")))
+
+		for _, key := range relation.JoinTableHandler.SourceForeignKeys() {
+			keyColumns = append(keyColumns, scope.Quote(joinTableName+"."+key.DBName))
+			if field, ok := scope.FieldByName(key.AssociationDBName); ok {
+				parentFieldNames = append(parentFieldNames, field.Name)
+			}
+		}
+	default:
+		scope.Err(fmt.Errorf("can't preload count of %v relation %s", relation.Kind, name))
+		return
+	}
+
+	counts := map[string]int64{}
+	if parentKeys := scope.getColumnAsArray(parentFieldNames, scope.Value); len(parentKeys) > 0 {
+		keyCondition := strings.Join(keyColumns, ",")
+		if len(keyColumns) > 1 {
+			keyCondition = "(" + keyCondition + ")"
+		}
+
+		preloadDB = preloadDB.Where(fmt.Sprintf("%v IN (%v)", keyCondition, toQueryMarks(parentKeys)), toQueryValues(parentKeys)...)
+		if len(preloadConditions) > 0 {
+			preloadDB = preloadDB.Where(preloadConditions[0], preloadConditions[1:]...)
+		}
+
+		rows, err := preloadDB.Select(strings.Join(keyColumns, ",") + ", COUNT(*)").Group(strings.Join(keyColumns, ",")).Rows()
+		if scope.Err(err) != nil {
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				keys   = make([]interface{}, len(keyColumns))
+				count  int64
+				values []interface{}
+			)
+			for idx := range keys {
+				values = append(values, &keys[idx])
+			}
+
+			if scope.Err(rows.Scan(append(values, &count)...)) != nil {
+				return
+			}
+			counts[toString(keys)] = count
+		}
+	}
+
+	// assign counts, parents without associations are set to 0
+	setCount := func(object reflect.Value) {
+		count := counts[toString(getValueFromFields(object, parentFieldNames))]
+		switch field := object.FieldByName(countField.Name); field.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			field.SetInt(count)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			field.SetUint(uint64(count))
+		default:
+			scope.Err(fmt.Errorf("count field %s should be integer", countField.Name))
+		}
+	}
+
+	if indirectScopeValue := scope.IndirectValue(); indirectScopeValue.Kind() == reflect.Slice {
+		for j := 0; j < indirectScopeValue.Len(); j++ {
+			setCount(indirect(indirectScopeValue.Index(j)))
+		}
+	} else {
+		setCount(indirectScopeValue)
+	}
+}
+
+// defaultPreloadMaxDepth how many levels of associations are preloaded with wildcard, could be changed with setting `gorm:preload_max_depth`
+const defaultPreloadMaxDepth = 3
+
+// expandPreloadWildcards expand `Preload("*")` and `Preload("Orders.*")` into preloads of every relationship,
+// relationships that refer to models already in the path are skipped, only scopes `func(*DB) *DB` are allowed as conditions
+func (scope *Scope) expandPreloadWildcards() ([]searchPreload, error) {
+	maxDepth := defaultPreloadMaxDepth
+	if depth, ok := scope.Get("gorm:preload_max_depth"); ok {
+		if depth, ok := depth.(int); ok && depth > 0 {
+			maxDepth = depth
+		}
+	}
+
+	var preloads []searchPreload
+	for _, preload := range scope.Search.preload {
+		if preload.schema != "*" && !strings.HasSuffix(preload.schema, ".*") {
+			preloads = append(preloads, preload)
+			continue
+		}
+
+		for _, condition := range preload.conditions {
+			if _, ok := condition.(func(*DB) *DB); !ok {
+				return nil, fmt.Errorf("wildcard preload %v only supports scopes as conditions", preload.schema)
+			}
+		}
+
+		var (
+			prefix      = strings.TrimSuffix(strings.TrimSuffix(preload.schema, "*"), ".")
+			modelStruct = scope.GetModelStruct()
+			visited     = map[reflect.Type]bool{modelStruct.ModelType: true}
+		)
+
+		if prefix != "" {
+			for _, name := range strings.Split(prefix, ".") {
+				field, ok := modelStruct.relationField(name)
+				if !ok {
+					return nil, fmt.Errorf("can't preload field %s for %s", name, modelStruct.ModelType)
+				}
+				modelStruct = scope.relationModelStruct(field)
+				visited[modelStruct.ModelType] = true
+			}
+			preloads = append(preloads, searchPreload{prefix, preload.conditions})
+			prefix += "."
+		}
+
+		var expand func(modelStruct *ModelStruct, prefix string, depth int)
+		expand = func(modelStruct *ModelStruct, prefix string, depth int) {
+			for _, field := range modelStruct.StructFields {
+				if field.Relationship == nil {
+					continue
+				}
+
+				relationStruct := scope.relationModelStruct(field)
+				if visited[relationStruct.ModelType] {
+					continue
+				}
+
+				preloads = append(preloads, searchPreload{prefix + field.Name, preload.conditions})
+				if depth < maxDepth {
+					visited[relationStruct.ModelType] = true
+					expand(relationStruct, prefix+field.Name+".", depth+1)
+					visited[relationStruct.ModelType] = false
+				}
+			}
+		}
+		expand(modelStruct, prefix, 1)
+	}
+	return preloads, nil
+}
+
+func (modelStruct *ModelStruct) relationField(name string) (*StructField, bool) {
+	for _, field := range modelStruct.StructFields {
+		if field.Name == name && field.Relationship != nil {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+func (modelStruct *ModelStruct) hasRelations() bool {
+	for _, field := range modelStruct.StructFields {
+		if field.Relationship != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (scope *Scope) relationModelStruct(field *StructField) *ModelStruct {
+	relationType := field.Struct.Type
+	for relationType.Kind() == reflect.Slice || relationType.Kind() == reflect.Ptr {
+		relationType = relationType.Elem()
+	}
+	return scope.New(reflect.New(relationType).Interface()).GetModelStruct()
+}
+
+// limitPreloadPerParent apply limit and offset of preload conditions to records of every parent instead of the whole query,
+// records are numbered with `ROW_NUMBER() OVER (PARTITION BY foreign keys ORDER BY orders)`,
+// or found with LATERAL join for every parent if `gorm:preload_lateral` is set, e.g. for postgres,
+// limit is applied to the whole query if the dialect doesn't support window functions
+//     db.Preload("Comments", func(db *gorm.DB) *gorm.DB { return db.Order("id DESC").Limit(3) }).Find(&posts)
+func (scope *Scope) limitPreloadPerParent(preloadDB *DB, value interface{}, partitionColumns []string, parentColumns []string, parentKeys [][]interface{}) (*DB, error) {
+	limit, err := strconv.ParseInt(fmt.Sprint(preloadDB.search.limit), 0, 0)
+	if err != nil || limit < 0 || preloadDB.search.raw || len(parentKeys) == 0 {
+		return preloadDB, nil
+	}
+
+	offset, err := strconv.ParseInt(fmt.Sprint(preloadDB.search.offset), 0, 0)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	var (
+		lateral, _   = scope.Get("gorm:preload_lateral")
+		capabilities = scope.Dialect().Capabilities()
+		sql          string
+		vars         []interface{}
+	)
+
+	if lateral == true && !capabilities.LateralJoin {
+		return preloadDB, fmt.Errorf("gorm:preload_lateral is set, but %v doesn't support LATERAL joins", scope.Dialect().GetName())
+	}
+
+	if lateral == true {
+		var parentColumnAliases []string
+		for idx, column := range parentColumns {
+			alias := fmt.Sprintf("gorm_parent_%d", idx)
+			parentColumnAliases = append(parentColumnAliases, fmt.Sprintf("%v.%v AS %v", scope.QuotedTableName(), scope.Quote(column), alias))
+			preloadDB = preloadDB.Where(fmt.Sprintf("%v = gorm_parents.%v", partitionColumns[idx], alias))
+		}
+
+		var qualifiedParentColumns []string
+		for _, column := range parentColumns {
+			qualifiedParentColumns = append(qualifiedParentColumns, fmt.Sprintf("%v.%v", scope.QuotedTableName(), scope.Quote(column)))
+		}
+
+		parentCondition := strings.Join(qualifiedParentColumns, ",")
+		if len(qualifiedParentColumns) > 1 {
+			parentCondition = "(" + parentCondition + ")"
+		}
+		vars = toQueryValues(parentKeys)
+
+		queryScope := preloadDB.NewScope(value)
+		queryScope.skipBindVar = true
+		sql = fmt.Sprintf("SELECT gorm_preload.* FROM (SELECT DISTINCT %v FROM %v WHERE %v IN (%v)) gorm_parents CROSS JOIN LATERAL (SELECT %v FROM %v %v) gorm_preload",
+			strings.Join(parentColumnAliases, ", "), scope.QuotedTableName(), parentCondition, toQueryMarks(parentKeys),
+			queryScope.selectSQL(), queryScope.QuotedTableName(), queryScope.CombinedConditionSql())
+		vars = append(vars, queryScope.SQLVars...)
+	} else if capabilities.WindowFunctions {
+		queryScope := preloadDB.NewScope(value)
+		queryScope.skipBindVar = true
+		queryScope.Search.limit, queryScope.Search.offset = -1, -1
+
+		selectSQL := queryScope.selectSQL()
+		orderSQL := queryScope.orderSQL()
+		if orderSQL == "" {
+			orderSQL = " ORDER BY " + strings.Join(partitionColumns, ",")
+		}
+		queryScope.Search.orders = nil
+
+		sql = fmt.Sprintf("SELECT * FROM (SELECT %v, ROW_NUMBER() OVER (PARTITION BY %v%v) AS gorm_preload_row FROM %v %v) gorm_preload WHERE gorm_preload_row > %d AND gorm_preload_row <= %d ORDER BY gorm_preload_row",
+			selectSQL, strings.Join(partitionColumns, ","), orderSQL, queryScope.QuotedTableName(), queryScope.CombinedConditionSql(), offset, offset+limit)
+		vars = queryScope.SQLVars
+	} else {
+		return preloadDB, nil
+	}
+
+	return preloadDB.New().Unscoped().Raw(sql, vars...), nil
 }
 
 func (scope *Scope) generatePreloadDBWithConditions(conditions []interface{}) (*DB, []interface{}) {
@@ -167,7 +562,23 @@ func (scope *Scope) handleHasManyPreload(field *Field, conditions []interface{})
 	}
 
 	results := makeSlice(field.Struct.Type)
-	scope.Err(preloadDB.Where(query, values...).Find(results, preloadConditions...).Error)
+	preloadDB = preloadDB.Where(query, values...)
+	if len(preloadConditions) > 0 {
+		preloadDB = preloadDB.Where(preloadConditions[0], preloadConditions[1:]...)
+	}
+
+	var (
+		relatedScope     = scope.New(results)
+		partitionColumns []string
+	)
+	for _, dbName := range relation.ForeignDBNames {
+		partitionColumns = append(partitionColumns, fmt.Sprintf("%v.%v", relatedScope.QuotedTableName(), scope.Quote(dbName)))
+	}
+	preloadDB, err := scope.limitPreloadPerParent(preloadDB, results, partitionColumns, relation.AssociationForeignDBNames, primaryKeys)
+	if scope.Err(err) != nil {
+		return
+	}
+	scope.Err(preloadDB.Find(results).Error)
 
 	// assign find results
 	var (
@@ -270,6 +681,23 @@ func (scope *Scope) handleManyToManyPreload(field *Field, conditions []interface
 		preloadDB = preloadDB.Where(preloadConditions[0], preloadConditions[1:]...)
 	}
 
+	var (
+		joinTableName                   = joinTableHandler.Table(scope.db)
+		partitionColumns, parentColumns []string
+		parentFieldNames                []string
+	)
+	for _, key := range joinTableHandler.SourceForeignKeys() {
+		partitionColumns = append(partitionColumns, scope.Quote(joinTableName+"."+key.DBName))
+		parentColumns = append(parentColumns, key.AssociationDBName)
+		if field, ok := scope.FieldByName(key.AssociationDBName); ok {
+			parentFieldNames = append(parentFieldNames, field.Name)
+		}
+	}
+	preloadDB, err := scope.limitPreloadPerParent(preloadDB, newScope.Value, partitionColumns, parentColumns, scope.getColumnAsArray(parentFieldNames, scope.Value))
+	if scope.Err(err) != nil {
+		return
+	}
+
 	rows, err := preloadDB.Rows()
 
 	if scope.Err(err) != nil {
@@ -3,7 +3,9 @@ package gorm
 import "reflect"
 
 func beginTransactionCallback(scope *Scope) {
-	scope.Begin()
+	if !scope.db.parent.skipDefaultTransaction {
+		scope.Begin()
+	}
 }
 
 func commitOrRollbackTransactionCallback(scope *Scope) {
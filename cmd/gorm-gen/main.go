@@ -0,0 +1,65 @@
+// Command gorm-gen generate gorm models from an existing database, for example:
+//     gorm-gen -dialect sqlite3 -dsn legacy.db -package models -o models/models.go
+//     gorm-gen -dialect postgres -dsn "user=gorm dbname=gorm sslmode=disable" -tables users,emails
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/nkovacs/gorm"
+	_ "github.com/nkovacs/gorm/dialects/mssql"
+	_ "github.com/nkovacs/gorm/dialects/mysql"
+	_ "github.com/nkovacs/gorm/dialects/postgres"
+	_ "github.com/nkovacs/gorm/dialects/sqlite"
+	"github.com/nkovacs/gorm/gen"
+)
+
+func main() {
+	var (
+		dialect     = flag.String("dialect", "sqlite3", "database dialect, one of sqlite3, mysql, postgres, mssql")
+		dsn         = flag.String("dsn", "", "data source name used to connect database")
+		packageName = flag.String("package", "models", "package name of generated code")
+		output      = flag.String("o", "", "output file, write to stdout if blank")
+		tables      = flag.String("tables", "", "comma separated table names, generate all tables if blank")
+	)
+	flag.Parse()
+
+	if err := run(*dialect, *dsn, *packageName, *output, *tables); err != nil {
+		fmt.Fprintln(os.Stderr, "gorm-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dialect, dsn, packageName, output, tables string) error {
+	if dsn == "" {
+		return fmt.Errorf("dsn is required")
+	}
+
+	db, err := gorm.Open(dialect, dsn)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var tableNames []string
+	if tables != "" {
+		for _, tableName := range strings.Split(tables, ",") {
+			tableNames = append(tableNames, strings.TrimSpace(tableName))
+		}
+	}
+
+	source, err := gen.New(db, packageName).Generate(tableNames...)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return ioutil.WriteFile(output, source, 0644)
+}
@@ -0,0 +1,204 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// Config configuration of DB opened with `OpenWith`
+type Config struct {
+	// Logger logger used to print logs and errors, default logger prints to stdout
+	Logger logger
+	// NowFunc function used to get current time for `CreatedAt`, `UpdatedAt` and `DeletedAt`, global `NowFunc` is used if nil
+	NowFunc func() time.Time
+	// PrepareStmt prepare statements and cache them for later operations, statements are not cached in transactions
+	PrepareStmt bool
+	// SkipDefaultTransaction create, update and delete don't start a transaction when they are not run in one
+	SkipDefaultTransaction bool
+	// SingularTable use singular table names by default
+	SingularTable bool
+	// NamingStrategy naming strategy of tables, columns, indexes and constraints, `DefaultNamingStrategy` is used if nil
+	NamingStrategy NamingStrategy
+	// Dialect configuration of dialect
+	Dialect DialectConfig
+}
+
+// Option configure DB opened with `OpenWith`, `Config` is an option that replaces the whole configuration
+type Option interface {
+	apply(config *Config)
+}
+
+func (c Config) apply(config *Config) {
+	*config = c
+}
+
+type optionFunc func(config *Config)
+
+func (fc optionFunc) apply(config *Config) {
+	fc(config)
+}
+
+// WithLogger set logger of DB
+func WithLogger(log logger) Option {
+	return optionFunc(func(config *Config) { config.Logger = log })
+}
+
+// WithNowFunc set function used to get current time for `CreatedAt`, `UpdatedAt` and `DeletedAt`
+func WithNowFunc(nowFunc func() time.Time) Option {
+	return optionFunc(func(config *Config) { config.NowFunc = nowFunc })
+}
+
+// WithPrepareStmt prepare statements and cache them for later operations
+func WithPrepareStmt(enable bool) Option {
+	return optionFunc(func(config *Config) { config.PrepareStmt = enable })
+}
+
+// WithSkipDefaultTransaction don't start a transaction for create, update and delete
+func WithSkipDefaultTransaction(enable bool) Option {
+	return optionFunc(func(config *Config) { config.SkipDefaultTransaction = enable })
+}
+
+// WithSingularTable use singular table names by default
+func WithSingularTable(enable bool) Option {
+	return optionFunc(func(config *Config) { config.SingularTable = enable })
+}
+
+// WithNamingStrategy set naming strategy of tables, columns, indexes and constraints
+func WithNamingStrategy(namingStrategy NamingStrategy) Option {
+	return optionFunc(func(config *Config) { config.NamingStrategy = namingStrategy })
+}
+
+// WithDialectConfig set configuration of dialect
+func WithDialectConfig(dialectConfig DialectConfig) Option {
+	return optionFunc(func(config *Config) { config.Dialect = dialectConfig })
+}
+
+// OpenWith initialize a new DB with an existing connection, e.g. opened with `sql.OpenDB(connector)` or with an instrumented driver,
+// the driver need to be imported first, same as `Open`
+//     sqlDB := sql.OpenDB(connector)
+//     db, err := gorm.OpenWith("postgres", sqlDB, gorm.WithSingularTable(true), gorm.WithPrepareStmt(true))
+func OpenWith(dialect string, sqlDB *sql.DB, opts ...Option) (*DB, error) {
+	var config Config
+	for _, opt := range opts {
+		opt.apply(&config)
+	}
+
+	if config.Logger == nil {
+		config.Logger = defaultLogger
+	}
+
+	db := &DB{
+		dialect:                newDialect(dialect, sqlDB, config.Dialect),
+		logger:                 config.Logger,
+		callbacks:              DefaultCallback,
+		values:                 map[string]interface{}{},
+		db:                     sqlDB,
+		nowFunc:                config.NowFunc,
+		skipDefaultTransaction: config.SkipDefaultTransaction,
+	}
+	db.parent = db
+
+	if config.PrepareStmt {
+		db.db = &preparedStmtDB{DB: sqlDB, stmts: map[string]*sql.Stmt{}}
+	}
+
+	// the DB has its own naming strategy, so models of other DBs are not parsed again
+	if config.NamingStrategy == nil && config.SingularTable {
+		config.NamingStrategy = DefaultNamingStrategy{SingularTable: true}
+	}
+
+	if config.NamingStrategy != nil {
+		db.SetNamingStrategy(config.NamingStrategy)
+	}
+
+	return db, sqlDB.Ping() // Send a ping to make sure the database connection is alive.
+}
+
+// preparedStmtDB prepare statements and cache them with SQL, they are closed when the DB is closed
+type preparedStmtDB struct {
+	*sql.DB
+	mutex sync.RWMutex
+	stmts map[string]*sql.Stmt
+}
+
+func (db *preparedStmtDB) prepare(query string) (*sql.Stmt, error) {
+	db.mutex.RLock()
+	stmt, ok := db.stmts[query]
+	db.mutex.RUnlock()
+	if ok {
+		return stmt, nil
+	}
+
+	db.mutex.Lock()
+	defer db.mutex.Unlock()
+	if stmt, ok := db.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := db.DB.Prepare(query)
+	if err == nil {
+		db.stmts[query] = stmt
+	}
+	return stmt, err
+}
+
+func (db *preparedStmtDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := db.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Exec(args...)
+}
+
+func (db *preparedStmtDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := db.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.Query(args...)
+}
+
+func (db *preparedStmtDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	stmt, err := db.prepare(query)
+	if err != nil {
+		// sql.Row with error can't be created, run the query directly to get the same error
+		return db.DB.QueryRow(query, args...)
+	}
+	return stmt.QueryRow(args...)
+}
+
+func (db *preparedStmtDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	stmt, err := db.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+func (db *preparedStmtDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	stmt, err := db.prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.QueryContext(ctx, args...)
+}
+
+func (db *preparedStmtDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	stmt, err := db.prepare(query)
+	if err != nil {
+		return db.DB.QueryRowContext(ctx, query, args...)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+func (db *preparedStmtDB) Close() error {
+	db.mutex.Lock()
+	for query, stmt := range db.stmts {
+		stmt.Close()
+		delete(db.stmts, query)
+	}
+	db.mutex.Unlock()
+	return db.DB.Close()
+}
@@ -1,10 +1,11 @@
 package gorm_test
 
 import (
-	"os"
 	"reflect"
 	"testing"
 	"time"
+
+	"github.com/nkovacs/gorm"
 )
 
 func TestCreate(t *testing.T) {
@@ -58,7 +59,7 @@ func TestCreate(t *testing.T) {
 }
 
 func TestCreateWithAutoIncrement(t *testing.T) {
-	if dialect := os.Getenv("GORM_DIALECT"); dialect != "postgres" {
+	if DB.Dialect().Capabilities().AutoIncrement != gorm.AutoIncrementAnyColumn {
 		t.Skip("Skipping this because only postgres properly support auto_increment on a non-primary_key column")
 	}
 	user1 := User{}
@@ -73,8 +74,8 @@ func TestCreateWithAutoIncrement(t *testing.T) {
 }
 
 func TestCreateWithNoGORMPrimayKey(t *testing.T) {
-	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mssql" {
-		t.Skip("Skipping this because MSSQL will return identity only if the table has an Id column")
+	if DB.Dialect().Capabilities().IdentityInsert {
+		t.Skip("Skipping this because dialects with identity columns like MSSQL will return identity only if the table has an Id column")
 	}
 
 	jt := JoinTable{From: 1, To: 2}
@@ -115,6 +116,50 @@ func TestCreateWithNoStdPrimaryKeyAndDefaultValues(t *testing.T) {
 	}
 }
 
+func TestCreateWithDefaultValuesInCompatibilityMode(t *testing.T) {
+	db, err := gorm.OpenWith("compatibility_mode", DB.DB())
+	if err != nil {
+		t.Fatalf("No error should happen when open with existing connection, but got %+v", err)
+	}
+
+	// `INSERT INTO table () VALUES ()` is mysql only
+	if !db.Dialect().Capabilities().DefaultValues {
+		t.Errorf("Dialects in compatibility mode should create records without values with DEFAULT VALUES")
+	}
+}
+
+func TestCreateWithStringPrimaryKey(t *testing.T) {
+	type StringKeyItem struct {
+		Code string `gorm:"primary_key"`
+		Name string
+	}
+	DB.DropTableIfExists(&StringKeyItem{})
+	DB.AutoMigrate(&StringKeyItem{})
+
+	if err := DB.Create(&StringKeyItem{Code: "item_1", Name: "string key"}).Error; err != nil {
+		t.Errorf("No error should happen when create a record with string primary key, but got %v", err)
+	}
+
+	var item StringKeyItem
+	if err := DB.First(&item, "code = ?", "item_1").Error; err != nil || item.Name != "string key" {
+		t.Errorf("Should find record created with string primary key, but got %+v, %v", item, err)
+	}
+}
+
+func TestCreateWithCompositePrimaryKey(t *testing.T) {
+	DB.DropTableIfExists(&BatchItem{})
+	DB.AutoMigrate(&BatchItem{})
+
+	if err := DB.Create(&BatchItem{Shop: "a", Code: "1", Stock: 10}).Error; err != nil {
+		t.Errorf("No error should happen when create a record with composite primary key, but got %v", err)
+	}
+
+	var item BatchItem
+	if err := DB.First(&item, "shop = ? AND code = ?", "a", "1").Error; err != nil || item.Stock != 10 {
+		t.Errorf("Should find record created with composite primary key, but got %+v, %v", item, err)
+	}
+}
+
 func TestAnonymousScanner(t *testing.T) {
 	user := User{Name: "anonymous_scanner", Role: Role{Name: "admin"}}
 	DB.Save(&user)
@@ -177,3 +222,49 @@ func TestOmitWithCreate(t *testing.T) {
 		t.Errorf("Should not create omited relationships")
 	}
 }
+
+func TestCreateWithUpsert(t *testing.T) {
+	if DB.Dialect().Capabilities().Upsert == gorm.UpsertUnsupported {
+		if err := DB.Set("gorm:upsert", []string{"age"}).Create(&User{Name: "upsert"}).Error; err == nil {
+			t.Errorf("Should return error when upsert isn't supported")
+		}
+		return
+	}
+
+	user := User{Name: "upsert", Age: 10}
+	if err := DB.Save(&user).Error; err != nil {
+		t.Fatalf("No error should happen when create user, but got %v", err)
+	}
+
+	conflicting := User{Id: user.Id, Name: "upsert-ignored", Age: 20}
+	if err := DB.Set("gorm:upsert", []string{"age"}).Create(&conflicting).Error; err != nil {
+		t.Errorf("No error should happen when upsert, but got %v", err)
+	}
+
+	var result User
+	if err := DB.First(&result, user.Id).Error; err != nil || result.Age != 20 || result.Name != "upsert" {
+		t.Errorf("Only upsert columns of conflicting record should be updated, but got %+v, %v", result, err)
+	}
+}
+
+func TestCreateWithBoolDefaultValue(t *testing.T) {
+	type BoolDefault struct {
+		ID      int
+		Name    string
+		Enabled *bool `sql:"default:true;not null"`
+	}
+	DB.DropTableIfExists(&BoolDefault{})
+	if err := DB.AutoMigrate(&BoolDefault{}).Error; err != nil {
+		t.Fatalf("No error should happen when migrate bool default value, but got %v", err)
+	}
+	defer DB.DropTableIfExists(&BoolDefault{})
+
+	record := BoolDefault{Name: "bool default"}
+	if err := DB.Create(&record).Error; err != nil {
+		t.Errorf("No error should happen when create with bool default value, but got %v", err)
+	}
+
+	if record.Enabled == nil || !*record.Enabled {
+		t.Errorf("Default value of bool field should be true, but got %v", record.Enabled)
+	}
+}
@@ -18,6 +18,8 @@ type Dialect interface {
 
 	// BindVar return the placeholder for actual values in SQL statements, in many dbs it is "?", Postgres using $1
 	BindVar(i int) string
+	// Capabilities return features and syntaxes supported by the dialect
+	Capabilities() Capabilities
 	// Quote quotes field name to avoid SQL parsing exceptions by using a reserved word as a field name
 	Quote(key string) string
 	// DataTypeOf return data's sql type
@@ -38,7 +40,7 @@ type Dialect interface {
 	LimitAndOffsetSQL(limit, offset interface{}) string
 	// SelectFromDummyTable return select values, for most dbs, `SELECT values` just works, mysql needs `SELECT value FROM DUAL`
 	SelectFromDummyTable() string
-	// LastInsertIdReturningSuffix most dbs support LastInsertId, but postgres needs to use `RETURNING`
+	// LastInsertIdReturningSuffix most dbs support LastInsertId, but postgres needs to use `RETURNING`, it is only used if `Capabilities.Returning` is set
 	LastInsertIDReturningSuffix(tableName, columnName string) string
 
 	// BuildForeignKeyName returns a foreign key name for the given table, field and reference
@@ -48,18 +50,202 @@ type Dialect interface {
 	CurrentDatabase() string
 }
 
+// ConfigurableDialect dialect that uses configuration passed to `Open`, dialects not implementing it ignore the configuration
+type ConfigurableDialect interface {
+	// SetConfig set configuration of dialect, it is called after `SetDB`
+	SetConfig(config DialectConfig)
+}
+
+// ConstraintDialect dialect that checks constraints itself, INFORMATION_SCHEMA is queried for dialects not implementing it
+type ConstraintDialect interface {
+	// HasConstraint check has constraint or not, e.g. check constraints
+	HasConstraint(tableName string, constraintName string) bool
+}
+
+// InsertedIDDialect dialect without `RETURNING` that reads inserted id with a clause between columns and values of insert statement,
+// `LastInsertId` is used for dialects not implementing it
+type InsertedIDDialect interface {
+	// LastInsertIDOutputInterstitial return clause between columns and values of insert statement to read inserted id, mssql needs to use `OUTPUT INSERTED.id`
+	LastInsertIDOutputInterstitial(tableName, columnName string) string
+}
+
+// CommentDialect dialect that supports comments of tables and columns, comments are only migrated if the dialect implements it
+type CommentDialect interface {
+	// Comment return comment of table, or of column if columnName isn't blank
+	Comment(tableName string, columnName string) string
+	// CommentSQL return SQL to set comment of table, or of field's column if field isn't nil
+	CommentSQL(tableName string, field *StructField, comment string) string
+}
+
+// UpsertStyle syntax used to update conflicting records when creating with `gorm:upsert`
+type UpsertStyle int
+
+const (
+	// UpsertUnsupported conflicting records couldn't be updated, mssql
+	UpsertUnsupported UpsertStyle = iota
+	// UpsertOnConflict `INSERT ... ON CONFLICT (...) DO UPDATE SET ...`, postgres and sqlite
+	UpsertOnConflict
+	// UpsertOnDuplicateKey `INSERT ... ON DUPLICATE KEY UPDATE ...`, mysql
+	UpsertOnDuplicateKey
+)
+
+// AlterColumnStyle syntax used to change type of an existing column
+type AlterColumnStyle int
+
+const (
+	// AlterColumnUnsupported columns can't be changed, sqlite
+	AlterColumnUnsupported AlterColumnStyle = iota
+	// AlterColumnModify `ALTER TABLE ... MODIFY column type`, mysql
+	AlterColumnModify
+	// AlterColumnType `ALTER TABLE ... ALTER COLUMN column TYPE type`, postgres
+	AlterColumnType
+	// AlterColumnPlain `ALTER TABLE ... ALTER COLUMN column type`, mssql
+	AlterColumnPlain
+)
+
+// IndexMethodStyle position of index method, e.g. `USING btree`, in `CREATE INDEX`
+type IndexMethodStyle int
+
+const (
+	// IndexMethodUnsupported index method is ignored
+	IndexMethodUnsupported IndexMethodStyle = iota
+	// IndexMethodBeforeColumns `CREATE INDEX ... ON table USING method (columns)`, postgres
+	IndexMethodBeforeColumns
+	// IndexMethodAfterColumns `CREATE INDEX ... ON table (columns) USING METHOD`, mysql
+	IndexMethodAfterColumns
+)
+
+// AutoIncrementStyle columns that could be auto incremented
+type AutoIncrementStyle int
+
+const (
+	// AutoIncrementPrimaryKey only a single integer primary key could be auto incremented, sqlite
+	AutoIncrementPrimaryKey AutoIncrementStyle = iota
+	// AutoIncrementKey one column of a primary key could be auto incremented, also if the primary key is composite, mysql and mssql
+	AutoIncrementKey
+	// AutoIncrementAnyColumn any integer column could be auto incremented, postgres
+	AutoIncrementAnyColumn
+)
+
+// Capabilities features and syntaxes supported by a dialect, core code checks them instead of dialect's name
+type Capabilities struct {
+	// Returning `INSERT ... RETURNING` is supported, inserted primary key is read with it instead of `LastInsertId`
+	Returning bool
+	// Savepoints `SAVEPOINT`, `ROLLBACK TO SAVEPOINT` and `RELEASE SAVEPOINT` are supported
+	Savepoints bool
+	// TransactionalDDL DDL statements could be rolled back, instead of committing the transaction implicitly
+	TransactionalDDL bool
+	// AlterColumn syntax used to change type of an existing column
+	AlterColumn AlterColumnStyle
+	// AlterConstraints foreign keys and check constraints could be added to an existing table
+	AlterConstraints bool
+	// DefaultValues `INSERT INTO table DEFAULT VALUES` is supported, otherwise `INSERT INTO table () VALUES ()` is used
+	DefaultValues bool
+	// MaxBindVars max count of placeholders in one SQL statement, 0 means unlimited
+	MaxBindVars int
+	// TrueLiteral and FalseLiteral boolean literals used in SQL, e.g. for default values like `default:true`
+	TrueLiteral  string
+	FalseLiteral string
+	// Upsert syntax used to update conflicting records
+	Upsert UpsertStyle
+	// PartialIndexes `CREATE INDEX ... WHERE ...` is supported
+	PartialIndexes bool
+	// IndexMethod position of index method in `CREATE INDEX`
+	IndexMethod IndexMethodStyle
+	// AutoIncrement columns that could be auto incremented
+	AutoIncrement AutoIncrementStyle
+	// TimeZones time values keep their time zone when saved
+	TimeZones bool
+	// OrderRequiredForOffset `ORDER BY` is required to use limit and offset
+	OrderRequiredForOffset bool
+	// IdentityInsert explicit values could be inserted into identity columns only after `SET IDENTITY_INSERT table ON`
+	IdentityInsert bool
+	// WindowFunctions `ROW_NUMBER() OVER (PARTITION BY ...)` is supported, preload limits are applied per parent with it
+	WindowFunctions bool
+	// LateralJoin `CROSS JOIN LATERAL (...)` is supported, preload limits are applied with it if `gorm:preload_lateral` is set
+	LateralJoin bool
+}
+
+// DialectConfig configuration of dialect, every DB has its own dialect instance with configuration passed to `Open`
+//     db, err := gorm.Open("mysql", "user:password@/dbname", gorm.DialectConfig{ServerVersion: "8.0.21", DefaultCharset: "utf8mb4"})
+type DialectConfig struct {
+	// DefaultStringSize size of string columns without tag `size`, dialect's default is used if it is 0
+	DefaultStringSize int
+	// ServerVersion version of database server, e.g. `5.7.31` or `10.5.8-MariaDB`, to decide supported column types and features
+	ServerVersion string
+	// DefaultCharset and DefaultCollation are used for string columns if set
+	DefaultCharset   string
+	DefaultCollation string
+}
+
+// StringSize return size of string field, DefaultStringSize is used if field doesn't have tag `size`
+func (config DialectConfig) StringSize(field *StructField, size int) int {
+	if _, ok := field.TagSettings["SIZE"]; !ok && config.DefaultStringSize > 0 {
+		return config.DefaultStringSize
+	}
+	return size
+}
+
+// serverVersionAtLeast check if ServerVersion is set and isn't older than version, e.g. `5.6.4`
+func (config DialectConfig) serverVersionAtLeast(version string) bool {
+	if config.ServerVersion == "" {
+		return false
+	}
+
+	current, minimum := versionNumbers(config.ServerVersion), versionNumbers(version)
+	for idx, number := range minimum {
+		if idx >= len(current) || current[idx] < number {
+			return false
+		}
+		if current[idx] > number {
+			return true
+		}
+	}
+	return true
+}
+
+// versionNumbers parse leading numbers of version like `10.5.8-MariaDB`
+func versionNumbers(version string) (numbers []int) {
+	for _, part := range strings.Split(version, ".") {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		number, err := strconv.Atoi(part[:end])
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, number)
+		if end < len(part) {
+			break
+		}
+	}
+	return
+}
+
 var dialectsMap = map[string]Dialect{}
 
-func newDialect(name string, db *sql.DB) Dialect {
+// newDialect create a copy of registered dialect for db, so DBs opened with the same dialect don't share db and configuration,
+// state of the registered dialect is kept in the copy
+func newDialect(name string, db *sql.DB, config DialectConfig) Dialect {
 	if value, ok := dialectsMap[name]; ok {
-		dialect := reflect.New(reflect.TypeOf(value).Elem()).Interface().(Dialect)
+		dialect := value
+		if reflectValue := reflect.ValueOf(value); reflectValue.Kind() == reflect.Ptr && !reflectValue.IsNil() {
+			copied := reflect.New(reflectValue.Type().Elem())
+			copied.Elem().Set(reflectValue.Elem())
+			dialect = copied.Interface().(Dialect)
+		}
 		dialect.SetDB(db)
+		if configurable, ok := dialect.(ConfigurableDialect); ok {
+			configurable.SetConfig(config)
+		}
 		return dialect
 	}
 
 	fmt.Printf("`%v` is not officially supported, running under compatibility mode.\n", name)
 	commontDialect := &commonDialect{}
 	commontDialect.SetDB(db)
+	commontDialect.SetConfig(config)
 	return commontDialect
 }
 
@@ -68,6 +254,17 @@ func RegisterDialect(name string, dialect Dialect) {
 	dialectsMap[name] = dialect
 }
 
+// GetDialect get registered dialect, e.g. to register it for another driver
+func GetDialect(name string) (dialect Dialect, ok bool) {
+	dialect, ok = dialectsMap[name]
+	return
+}
+
+// QuoteString quotes string as SQL string literal
+func QuoteString(str string) string {
+	return "'" + strings.Replace(str, "'", "''", -1) + "'"
+}
+
 // ParseFieldStructForDialect parse field struct for dialect
 func ParseFieldStructForDialect(field *StructField) (fieldValue reflect.Value, sqlType string, size int, additionalType string) {
 	// Get redirected field type
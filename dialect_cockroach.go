@@ -0,0 +1,134 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// cockroachRestartSavepoint savepoint used by CockroachDB's client-side transaction retry protocol
+const cockroachRestartSavepoint = "cockroach_restart"
+
+// defaultTransactionMaxRetries max retries of `Transaction` if not set with `gorm:transaction_max_retries`
+const defaultTransactionMaxRetries = 10
+
+type cockroach struct {
+	postgres
+}
+
+func init() {
+	RegisterDialect("cockroach", &cockroach{})
+}
+
+func (cockroach) GetName() string {
+	return "cockroach"
+}
+
+// Capabilities schema changes in CockroachDB are not transactional
+func (s cockroach) Capabilities() Capabilities {
+	capabilities := s.postgres.Capabilities()
+	capabilities.TransactionalDDL = false
+	return capabilities
+}
+
+// DataTypeOf auto increment fields use `unique_rowid()`, which is what `SERIAL` means in CockroachDB, its values don't fit into `integer`
+func (s cockroach) DataTypeOf(field *StructField) string {
+	sqlType := s.postgres.DataTypeOf(field)
+	for _, serial := range []string{"serial", "bigserial"} {
+		if sqlType == serial || strings.HasPrefix(sqlType, serial+" ") {
+			return "INT8 DEFAULT unique_rowid()" + strings.TrimPrefix(sqlType, serial)
+		}
+	}
+	return sqlType
+}
+
+func (s cockroach) ColumnTypes(tableName string) ([]ColumnType, error) {
+	columnTypes, err := s.postgres.ColumnTypes(tableName)
+	for idx := range columnTypes {
+		if strings.Contains(columnTypes[idx].Default.String, "unique_rowid()") {
+			columnTypes[idx].AutoIncrement = true
+		}
+	}
+	return columnTypes, err
+}
+
+// Indexes pg_index's int2vector columns couldn't be used as arrays in CockroachDB, indexes are read from INFORMATION_SCHEMA instead
+func (s cockroach) Indexes(tableName string) (indexes []TableIndex, err error) {
+	schema, table := splitTableName(tableName)
+	rows, err := s.db.Query(`SELECT st.index_name, st.column_name, st.non_unique = 'NO', tc.constraint_name IS NOT NULL
+		FROM INFORMATION_SCHEMA.statistics st
+		LEFT JOIN INFORMATION_SCHEMA.table_constraints tc ON tc.table_schema = st.table_schema AND tc.table_name = st.table_name AND tc.constraint_name = st.index_name AND tc.constraint_type = 'PRIMARY KEY'
+		WHERE st.table_schema = `+fmt.Sprintf(postgresSchemaSQL, "$1", "$2")+` AND st.table_name = $2 AND st.storing = 'NO' AND st.implicit = 'NO'
+		ORDER BY st.index_name, st.seq_in_index`, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, column    string
+			unique, primary bool
+		)
+
+		if err = rows.Scan(&name, &column, &unique, &primary); err != nil {
+			return nil, err
+		}
+		indexes = AppendIndexColumn(indexes, name, column, unique, primary)
+	}
+	return indexes, rows.Err()
+}
+
+// runTransaction run fc with CockroachDB's client-side retry protocol, the transaction is restarted from savepoint `cockroach_restart`
+// if fc or releasing the savepoint failed with retryable error (SQLSTATE 40001)
+func (s cockroach) runTransaction(tx *DB, fc func(tx *DB) error) error {
+	if !s.Capabilities().Savepoints {
+		return fc(tx)
+	}
+
+	if err := tx.Exec("SAVEPOINT " + cockroachRestartSavepoint).Error; err != nil {
+		return err
+	}
+
+	maxRetries := defaultTransactionMaxRetries
+	if value, ok := tx.Get("gorm:transaction_max_retries"); ok {
+		if retries, ok := value.(int); ok {
+			maxRetries = retries
+		}
+	}
+
+	for retries := 0; ; retries++ {
+		err := fc(tx)
+		if err == nil {
+			if err = tx.Exec("RELEASE SAVEPOINT " + cockroachRestartSavepoint).Error; err == nil {
+				return nil
+			}
+		}
+
+		if !isRetryableError(err) || retries >= maxRetries {
+			return err
+		}
+
+		if err := tx.Exec("ROLLBACK TO SAVEPOINT " + cockroachRestartSavepoint).Error; err != nil {
+			return err
+		}
+	}
+}
+
+// isRetryableError check if error, or any error of `Errors`, is serialization failure (SQLSTATE 40001)
+func isRetryableError(err error) bool {
+	if errs, ok := err.(errorsInterface); ok {
+		for _, err := range errs.GetErrors() {
+			if isRetryableError(err) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if sqlStateErr, ok := err.(interface {
+		SQLState() string
+	}); ok {
+		return sqlStateErr.SQLState() == "40001"
+	}
+	return false
+}
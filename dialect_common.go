@@ -15,7 +15,8 @@ type DefaultForeignKeyNamer struct {
 }
 
 type commonDialect struct {
-	db *sql.DB
+	db     *sql.DB
+	config DialectConfig
 	DefaultForeignKeyNamer
 }
 
@@ -31,16 +32,34 @@ func (s *commonDialect) SetDB(db *sql.DB) {
 	s.db = db
 }
 
+func (s *commonDialect) SetConfig(config DialectConfig) {
+	s.config = config
+}
+
 func (commonDialect) BindVar(i int) string {
 	return "$$" // ?
 }
 
+func (commonDialect) Capabilities() Capabilities {
+	return Capabilities{
+		Savepoints:       true,
+		AlterColumn:      AlterColumnModify,
+		AlterConstraints: true,
+		DefaultValues:    true,
+		TrueLiteral:      "TRUE",
+		FalseLiteral:     "FALSE",
+		AutoIncrement:    AutoIncrementKey,
+		TimeZones:        true,
+	}
+}
+
 func (commonDialect) Quote(key string) string {
 	return fmt.Sprintf(`"%s"`, key)
 }
 
-func (commonDialect) DataTypeOf(field *StructField) string {
+func (s commonDialect) DataTypeOf(field *StructField) string {
 	var dataValue, sqlType, size, additionalType = ParseFieldStructForDialect(field)
+	size = s.config.StringSize(field, size)
 
 	if v, ok := field.TagSettings["AUTO_INCREMENT"]; ok && v == "FALSE" {
 		delete(field.TagSettings, "AUTO_INCREMENT")
@@ -110,6 +129,12 @@ func (s commonDialect) HasForeignKey(tableName string, foreignKeyName string) bo
 	return false
 }
 
+func (s commonDialect) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE table_schema = ? AND table_name = ? AND constraint_name = ?", s.CurrentDatabase(), tableName, constraintName).Scan(&count)
+	return count > 0
+}
+
 func (s commonDialect) HasTable(tableName string) bool {
 	var count int
 	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_name = ?", s.CurrentDatabase(), tableName).Scan(&count)
@@ -154,3 +179,85 @@ func (DefaultForeignKeyNamer) BuildForeignKeyName(tableName, field, dest string)
 	keyName = regexp.MustCompile("(_*[^a-zA-Z]+_*|_+)").ReplaceAllString(keyName, "_")
 	return keyName
 }
+
+func (s commonDialect) Tables() ([]string, error) {
+	return ScanStrings(s.db.Query("SELECT table_name FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_type = 'BASE TABLE' ORDER BY table_name", s.CurrentDatabase()))
+}
+
+// ColumnTypes read standard columns of INFORMATION_SCHEMA, primary key and unique columns are marked with indexes
+func (s commonDialect) ColumnTypes(tableName string) (columnTypes []ColumnType, err error) {
+	rows, err := s.db.Query("SELECT column_name, data_type, character_maximum_length, numeric_precision, numeric_scale, is_nullable, column_default FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position", s.CurrentDatabase(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			columnType               ColumnType
+			length, precision, scale sql.NullInt64
+			nullable                 string
+		)
+
+		if err = rows.Scan(&columnType.Name, &columnType.DatabaseType, &length, &precision, &scale, &nullable, &columnType.Default); err != nil {
+			return nil, err
+		}
+
+		columnType.Length, columnType.Precision, columnType.Scale = length.Int64, precision.Int64, scale.Int64
+		columnType.Nullable = nullable == "YES"
+		columnTypes = append(columnTypes, columnType)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes, err := s.Indexes(tableName)
+	if err != nil {
+		return nil, err
+	}
+	MarkUniqueColumns(columnTypes, indexes)
+	return columnTypes, nil
+}
+
+func (s commonDialect) Indexes(tableName string) (indexes []TableIndex, err error) {
+	rows, err := s.db.Query("SELECT index_name, column_name, non_unique FROM INFORMATION_SCHEMA.STATISTICS WHERE table_schema = ? AND table_name = ? ORDER BY index_name, seq_in_index", s.CurrentDatabase(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, column string
+			nonUnique    bool
+		)
+
+		if err = rows.Scan(&name, &column, &nonUnique); err != nil {
+			return nil, err
+		}
+		indexes = AppendIndexColumn(indexes, name, column, !nonUnique, name == "PRIMARY")
+	}
+	return indexes, rows.Err()
+}
+
+func (s commonDialect) ForeignKeys(tableName string) (foreignKeys []TableForeignKey, err error) {
+	rows, err := s.db.Query(`SELECT kcu.constraint_name, kcu.column_name, kcu.referenced_table_name, kcu.referenced_column_name, rc.delete_rule, rc.update_rule
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+		JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc ON rc.constraint_schema = kcu.constraint_schema AND rc.constraint_name = kcu.constraint_name
+		WHERE kcu.table_schema = ? AND kcu.table_name = ? AND kcu.referenced_table_name IS NOT NULL
+		ORDER BY kcu.constraint_name, kcu.ordinal_position`, s.CurrentDatabase(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, column, referencedTable, referencedColumn, onDelete, onUpdate string
+		if err = rows.Scan(&name, &column, &referencedTable, &referencedColumn, &onDelete, &onUpdate); err != nil {
+			return nil, err
+		}
+		foreignKeys = AppendForeignKeyColumn(foreignKeys, name, column, referencedTable, referencedColumn, onDelete, onUpdate)
+	}
+	return foreignKeys, rows.Err()
+}
@@ -2,6 +2,7 @@ package gorm
 
 import (
 	"crypto/sha1"
+	"database/sql"
 	"fmt"
 	"reflect"
 	"regexp"
@@ -22,13 +23,33 @@ func (mysql) GetName() string {
 	return "mysql"
 }
 
+// Capabilities window functions are supported since mysql 8.0 and mariadb 10.2, LATERAL joins since mysql 8.0.14,
+// they are not used unless ServerVersion is set
+func (s mysql) Capabilities() Capabilities {
+	return Capabilities{
+		Savepoints:       true,
+		AlterColumn:      AlterColumnModify,
+		AlterConstraints: true,
+		MaxBindVars:      65535,
+		TrueLiteral:      "TRUE",
+		FalseLiteral:     "FALSE",
+		Upsert:           UpsertOnDuplicateKey,
+		IndexMethod:      IndexMethodAfterColumns,
+		AutoIncrement:    AutoIncrementKey,
+		TimeZones:        true,
+		WindowFunctions:  s.isMariaDB() && s.config.serverVersionAtLeast("10.2") || !s.isMariaDB() && s.config.serverVersionAtLeast("8.0"),
+		LateralJoin:      !s.isMariaDB() && s.config.serverVersionAtLeast("8.0.14"),
+	}
+}
+
 func (mysql) Quote(key string) string {
 	return fmt.Sprintf("`%s`", key)
 }
 
 // Get Data Type for MySQL Dialect
-func (mysql) DataTypeOf(field *StructField) string {
+func (s mysql) DataTypeOf(field *StructField) string {
 	var dataValue, sqlType, size, additionalType = ParseFieldStructForDialect(field)
+	size = s.config.StringSize(field, size)
 
 	forceNoIncrement := false
 	if v, ok := field.TagSettings["AUTO_INCREMENT"]; ok && v == "FALSE" {
@@ -84,12 +105,23 @@ func (mysql) DataTypeOf(field *StructField) string {
 			} else {
 				sqlType = "longtext"
 			}
+
+			if s.config.DefaultCharset != "" {
+				sqlType += " CHARACTER SET " + s.config.DefaultCharset
+			}
+			if s.config.DefaultCollation != "" {
+				sqlType += " COLLATE " + s.config.DefaultCollation
+			}
 		case reflect.Struct:
 			if _, ok := dataValue.Interface().(time.Time); ok {
-				if _, ok := field.TagSettings["NOT NULL"]; ok {
-					sqlType = "timestamp"
-				} else {
-					sqlType = "timestamp NULL"
+				sqlType = "timestamp"
+				// fractional seconds are supported since mysql 5.6.4 and mariadb 5.3
+				if s.supportsFractionalSeconds() {
+					sqlType = "timestamp(6)"
+				}
+
+				if _, ok := field.TagSettings["NOT NULL"]; !ok {
+					sqlType += " NULL"
 				}
 			}
 		default:
@@ -107,12 +139,27 @@ func (mysql) DataTypeOf(field *StructField) string {
 		panic(fmt.Sprintf("invalid sql type %s (%s) for mysql", dataValue.Type().Name(), dataValue.Kind().String()))
 	}
 
+	if comment, ok := field.TagSettings["COMMENT"]; ok {
+		additionalType = strings.TrimSpace(additionalType + " COMMENT " + QuoteString(comment))
+	}
+
 	if strings.TrimSpace(additionalType) == "" {
 		return sqlType
 	}
 	return fmt.Sprintf("%v %v", sqlType, additionalType)
 }
 
+func (s mysql) supportsFractionalSeconds() bool {
+	if s.isMariaDB() {
+		return s.config.serverVersionAtLeast("5.3")
+	}
+	return s.config.serverVersionAtLeast("5.6.4")
+}
+
+func (s mysql) isMariaDB() bool {
+	return strings.Contains(strings.ToLower(s.config.ServerVersion), "mariadb")
+}
+
 func (s mysql) RemoveIndex(tableName string, indexName string) error {
 	_, err := s.db.Exec(fmt.Sprintf("DROP INDEX %v ON %v", indexName, s.Quote(tableName)))
 	return err
@@ -124,6 +171,55 @@ func (s mysql) HasForeignKey(tableName string, foreignKeyName string) bool {
 	return count > 0
 }
 
+func (s mysql) Comment(tableName string, columnName string) (comment string) {
+	if columnName == "" {
+		s.db.QueryRow("SELECT table_comment FROM INFORMATION_SCHEMA.TABLES WHERE table_schema = ? AND table_name = ?", s.CurrentDatabase(), tableName).Scan(&comment)
+	} else {
+		s.db.QueryRow("SELECT column_comment FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ? AND column_name = ?", s.CurrentDatabase(), tableName, columnName).Scan(&comment)
+	}
+	return
+}
+
+func (s mysql) CommentSQL(tableName string, field *StructField, comment string) string {
+	if field == nil {
+		return fmt.Sprintf("ALTER TABLE %v COMMENT = %v", s.Quote(tableName), QuoteString(comment))
+	}
+
+	// column's type is generated from field, which contains the comment
+	field = field.clone()
+	field.TagSettings["COMMENT"] = comment
+	return fmt.Sprintf("ALTER TABLE %v MODIFY COLUMN %v %v", s.Quote(tableName), s.Quote(field.DBName), s.DataTypeOf(field))
+}
+
+// ColumnTypes auto increment columns and comments are read from MySQL's extra columns of INFORMATION_SCHEMA, `tinyint(1)` booleans get length 1
+func (s mysql) ColumnTypes(tableName string) (columnTypes []ColumnType, err error) {
+	rows, err := s.db.Query("SELECT column_name, data_type, IF(column_type = 'tinyint(1)', 1, character_maximum_length), numeric_precision, numeric_scale, is_nullable, column_default, column_key, extra, column_comment FROM INFORMATION_SCHEMA.COLUMNS WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position", s.CurrentDatabase(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			columnType                 ColumnType
+			length, precision, scale   sql.NullInt64
+			nullable, columnKey, extra string
+		)
+
+		if err = rows.Scan(&columnType.Name, &columnType.DatabaseType, &length, &precision, &scale, &nullable, &columnType.Default, &columnKey, &extra, &columnType.Comment); err != nil {
+			return nil, err
+		}
+
+		columnType.Length, columnType.Precision, columnType.Scale = length.Int64, precision.Int64, scale.Int64
+		columnType.Nullable = nullable == "YES"
+		columnType.PrimaryKey = columnKey == "PRI"
+		columnType.Unique = columnKey == "UNI"
+		columnType.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		columnTypes = append(columnTypes, columnType)
+	}
+	return columnTypes, rows.Err()
+}
+
 func (s mysql) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT DATABASE()").Scan(&name)
 	return
@@ -1,6 +1,7 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
 	"strings"
@@ -23,7 +24,28 @@ func (postgres) BindVar(i int) string {
 	return fmt.Sprintf("$%v", i)
 }
 
-func (postgres) DataTypeOf(field *StructField) string {
+func (postgres) Capabilities() Capabilities {
+	return Capabilities{
+		Returning:        true,
+		Savepoints:       true,
+		TransactionalDDL: true,
+		AlterColumn:      AlterColumnType,
+		AlterConstraints: true,
+		DefaultValues:    true,
+		MaxBindVars:      65535,
+		TrueLiteral:      "TRUE",
+		FalseLiteral:     "FALSE",
+		Upsert:           UpsertOnConflict,
+		PartialIndexes:   true,
+		IndexMethod:      IndexMethodBeforeColumns,
+		AutoIncrement:    AutoIncrementAnyColumn,
+		TimeZones:        true,
+		WindowFunctions:  true,
+		LateralJoin:      true,
+	}
+}
+
+func (s postgres) DataTypeOf(field *StructField) string {
 	var dataValue, sqlType, size, additionalType = ParseFieldStructForDialect(field)
 
 	forceNoIncrement := false
@@ -54,7 +76,7 @@ func (postgres) DataTypeOf(field *StructField) string {
 			sqlType = "numeric"
 		case reflect.String:
 			if _, ok := field.TagSettings["SIZE"]; !ok {
-				size = 0 // if SIZE haven't been set, use `text` as the default type, as there are no performance different
+				size = s.config.DefaultStringSize // if SIZE haven't been set, use `text` as the default type, as there are no performance different
 			}
 
 			if size > 0 && size < 65532 {
@@ -62,6 +84,10 @@ func (postgres) DataTypeOf(field *StructField) string {
 			} else {
 				sqlType = "text"
 			}
+
+			if s.config.DefaultCollation != "" {
+				sqlType += " COLLATE " + s.Quote(s.config.DefaultCollation)
+			}
 		case reflect.Struct:
 			if _, ok := dataValue.Interface().(time.Time); ok {
 				sqlType = "timestamp with time zone"
@@ -89,30 +115,87 @@ func (postgres) DataTypeOf(field *StructField) string {
 	return fmt.Sprintf("%v %v", sqlType, additionalType)
 }
 
+// postgresSchemaSQL schema of table, if table name isn't qualified, it is the schema where search_path finds the table,
+// or the first existing schema of search_path if the table doesn't exist
+const postgresSchemaSQL = "COALESCE(NULLIF(%[1]v, ''), (SELECT n.nspname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relname = %[2]v::text AND c.relkind IN ('r', 'p', 'v') AND pg_table_is_visible(c.oid)), CURRENT_SCHEMA())"
+
+// splitTableName split schema-qualified table name like `reporting.events`, schema is blank if table name isn't qualified
+func splitTableName(tableName string) (schema string, table string) {
+	if idx := strings.LastIndex(tableName, "."); idx >= 0 {
+		return tableName[:idx], tableName[idx+1:]
+	}
+	return "", tableName
+}
+
+// quoteTableName quote every part of schema-qualified table name
+func (s postgres) quoteTableName(tableName string) string {
+	if schema, table := splitTableName(tableName); schema != "" {
+		return s.Quote(schema) + "." + s.Quote(table)
+	}
+	return s.Quote(tableName)
+}
+
+func (s postgres) RemoveIndex(tableName string, indexName string) error {
+	// index is in the same schema as its table
+	if schema, _ := splitTableName(tableName); schema != "" {
+		indexName = s.Quote(schema) + "." + indexName
+	}
+	_, err := s.db.Exec(fmt.Sprintf("DROP INDEX %v", indexName))
+	return err
+}
+
 func (s postgres) HasIndex(tableName string, indexName string) bool {
 	var count int
-	s.db.QueryRow("SELECT count(*) FROM pg_indexes WHERE tablename = $1 AND indexname = $2", tableName, indexName).Scan(&count)
+	schema, table := splitTableName(tableName)
+	s.db.QueryRow("SELECT count(*) FROM pg_indexes WHERE schemaname = "+fmt.Sprintf(postgresSchemaSQL, "$1", "$2")+" AND tablename = $2 AND indexname = $3", schema, table, indexName).Scan(&count)
 	return count > 0
 }
 
 func (s postgres) HasForeignKey(tableName string, foreignKeyName string) bool {
 	var count int
-	s.db.QueryRow("SELECT count(con.conname) FROM pg_constraint con WHERE $1::regclass::oid = con.conrelid AND con.conname = $2 AND con.contype='f'", tableName, foreignKeyName).Scan(&count)
+	s.db.QueryRow("SELECT count(con.conname) FROM pg_constraint con WHERE $1::regclass::oid = con.conrelid AND con.conname = $2 AND con.contype='f'", s.quoteTableName(tableName), foreignKeyName).Scan(&count)
+	return count > 0
+}
+
+func (s postgres) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(con.conname) FROM pg_constraint con WHERE $1::regclass::oid = con.conrelid AND con.conname = $2", s.quoteTableName(tableName), constraintName).Scan(&count)
 	return count > 0
 }
 
 func (s postgres) HasTable(tableName string) bool {
 	var count int
-	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1 AND table_type = 'BASE TABLE'", tableName).Scan(&count)
+	schema, table := splitTableName(tableName)
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_schema = "+fmt.Sprintf(postgresSchemaSQL, "$1", "$2")+" AND table_name = $2 AND table_type = 'BASE TABLE'", schema, table).Scan(&count)
 	return count > 0
 }
 
 func (s postgres) HasColumn(tableName string, columnName string) bool {
 	var count int
-	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2", tableName, columnName).Scan(&count)
+	schema, table := splitTableName(tableName)
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_schema = "+fmt.Sprintf(postgresSchemaSQL, "$1", "$2")+" AND table_name = $2 AND column_name = $3", schema, table, columnName).Scan(&count)
 	return count > 0
 }
 
+func (s postgres) Comment(tableName string, columnName string) string {
+	var comment sql.NullString
+	if columnName == "" {
+		s.db.QueryRow("SELECT obj_description($1::regclass, 'pg_class')", s.quoteTableName(tableName)).Scan(&comment)
+	} else {
+		schema, table := splitTableName(tableName)
+		s.db.QueryRow("SELECT col_description($1::regclass, ordinal_position) FROM INFORMATION_SCHEMA.columns WHERE table_schema = "+fmt.Sprintf(postgresSchemaSQL, "$2", "$3")+" AND table_name = $3 AND column_name = $4",
+			s.quoteTableName(tableName), schema, table, columnName).Scan(&comment)
+	}
+	return comment.String
+}
+
+func (s postgres) CommentSQL(tableName string, field *StructField, comment string) string {
+	if field == nil {
+		return fmt.Sprintf("COMMENT ON TABLE %v IS %v", s.quoteTableName(tableName), QuoteString(comment))
+	}
+	return fmt.Sprintf("COMMENT ON COLUMN %v.%v IS %v", s.quoteTableName(tableName), s.Quote(field.DBName), QuoteString(comment))
+}
+
 func (s postgres) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT CURRENT_DATABASE()").Scan(&name)
 	return
@@ -126,6 +209,96 @@ func (postgres) SupportLastInsertID() bool {
 	return false
 }
 
+func (s postgres) Tables() ([]string, error) {
+	return ScanStrings(s.db.Query("SELECT table_name FROM INFORMATION_SCHEMA.tables WHERE table_schema = CURRENT_SCHEMA() AND table_type = 'BASE TABLE' ORDER BY table_name"))
+}
+
+func (s postgres) ColumnTypes(tableName string) (columnTypes []ColumnType, err error) {
+	schema, table := splitTableName(tableName)
+	rows, err := s.db.Query(`SELECT column_name, data_type, character_maximum_length, numeric_precision, numeric_scale, is_nullable, column_default, col_description($1::regclass, ordinal_position)
+		FROM INFORMATION_SCHEMA.columns WHERE table_schema = `+fmt.Sprintf(postgresSchemaSQL, "$2", "$3")+` AND table_name = $3 ORDER BY ordinal_position`, s.quoteTableName(tableName), schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			columnType               ColumnType
+			length, precision, scale sql.NullInt64
+			nullable                 string
+			comment                  sql.NullString
+		)
+
+		if err = rows.Scan(&columnType.Name, &columnType.DatabaseType, &length, &precision, &scale, &nullable, &columnType.Default, &comment); err != nil {
+			return nil, err
+		}
+
+		columnType.Length, columnType.Precision, columnType.Scale = length.Int64, precision.Int64, scale.Int64
+		columnType.Nullable = nullable == "YES"
+		columnType.AutoIncrement = strings.HasPrefix(columnType.Default.String, "nextval(")
+		columnType.Comment = comment.String
+		columnTypes = append(columnTypes, columnType)
+	}
+
+	if err = rows.Err(); err == nil {
+		var indexes []TableIndex
+		if indexes, err = s.Indexes(tableName); err == nil {
+			MarkUniqueColumns(columnTypes, indexes)
+		}
+	}
+	return columnTypes, err
+}
+
+func (s postgres) Indexes(tableName string) (indexes []TableIndex, err error) {
+	rows, err := s.db.Query(`SELECT ic.relname, a.attname, ix.indisunique, ix.indisprimary
+		FROM pg_index ix
+		JOIN pg_class ic ON ic.oid = ix.indexrelid
+		JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = ANY(ix.indkey)
+		WHERE ix.indrelid = $1::regclass
+		ORDER BY ic.relname, array_position(ix.indkey::int2[], a.attnum)`, s.quoteTableName(tableName))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, column    string
+			unique, primary bool
+		)
+
+		if err = rows.Scan(&name, &column, &unique, &primary); err != nil {
+			return nil, err
+		}
+		indexes = AppendIndexColumn(indexes, name, column, unique, primary)
+	}
+	return indexes, rows.Err()
+}
+
+func (s postgres) ForeignKeys(tableName string) (foreignKeys []TableForeignKey, err error) {
+	schema, table := splitTableName(tableName)
+	rows, err := s.db.Query(`SELECT kcu.constraint_name, kcu.column_name, ref.table_name, ref.column_name, rc.delete_rule, rc.update_rule
+		FROM INFORMATION_SCHEMA.referential_constraints rc
+		JOIN INFORMATION_SCHEMA.key_column_usage kcu ON kcu.constraint_schema = rc.constraint_schema AND kcu.constraint_name = rc.constraint_name
+		JOIN INFORMATION_SCHEMA.key_column_usage ref ON ref.constraint_schema = rc.unique_constraint_schema AND ref.constraint_name = rc.unique_constraint_name AND ref.ordinal_position = kcu.position_in_unique_constraint
+		WHERE kcu.table_schema = `+fmt.Sprintf(postgresSchemaSQL, "$1", "$2")+` AND kcu.table_name = $2
+		ORDER BY kcu.constraint_name, kcu.ordinal_position`, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, column, referencedTable, referencedColumn, onDelete, onUpdate string
+		if err = rows.Scan(&name, &column, &referencedTable, &referencedColumn, &onDelete, &onUpdate); err != nil {
+			return nil, err
+		}
+		foreignKeys = AppendForeignKeyColumn(foreignKeys, name, column, referencedTable, referencedColumn, onDelete, onUpdate)
+	}
+	return foreignKeys, rows.Err()
+}
+
 func isByteArrayOrSlice(value reflect.Value) bool {
 	return (value.Kind() == reflect.Array || value.Kind() == reflect.Slice) && value.Type().Elem() == reflect.TypeOf(uint8(0))
 }
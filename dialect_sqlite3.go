@@ -1,8 +1,10 @@
 package gorm
 
 import (
+	"database/sql"
 	"fmt"
 	"reflect"
+	"regexp"
 	"strings"
 	"time"
 )
@@ -20,9 +22,44 @@ func (sqlite3) GetName() string {
 	return "sqlite3"
 }
 
+// SetConfig sqlite is embedded in the driver, its version is read from the database if ServerVersion isn't set
+func (s *sqlite3) SetConfig(config DialectConfig) {
+	if config.ServerVersion == "" && s.db != nil {
+		s.db.QueryRow("SELECT sqlite_version()").Scan(&config.ServerVersion)
+	}
+	s.config = config
+}
+
+// Capabilities sqlite before 3.32.0 is compiled with SQLITE_MAX_VARIABLE_NUMBER 999, `RETURNING` requires 3.35.0,
+// upsert requires 3.24.0, window functions require 3.25.0, `TRUE` and `FALSE` require 3.23.0 so `1` and `0` are used
+func (s sqlite3) Capabilities() Capabilities {
+	capabilities := Capabilities{
+		Returning:        s.config.serverVersionAtLeast("3.35"),
+		Savepoints:       true,
+		TransactionalDDL: true,
+		DefaultValues:    true,
+		MaxBindVars:      999,
+		TrueLiteral:      "1",
+		FalseLiteral:     "0",
+		PartialIndexes:   true,
+		TimeZones:        true,
+		WindowFunctions:  s.config.serverVersionAtLeast("3.25"),
+	}
+
+	if s.config.serverVersionAtLeast("3.24") {
+		capabilities.Upsert = UpsertOnConflict
+	}
+	return capabilities
+}
+
+func (s sqlite3) LastInsertIDReturningSuffix(tableName, key string) string {
+	return fmt.Sprintf("RETURNING %v", key)
+}
+
 // Get Data Type for Sqlite Dialect
-func (sqlite3) DataTypeOf(field *StructField) string {
+func (s sqlite3) DataTypeOf(field *StructField) string {
 	var dataValue, sqlType, size, additionalType = ParseFieldStructForDialect(field)
+	size = s.config.StringSize(field, size)
 
 	if sqlType == "" {
 		switch dataValue.Kind() {
@@ -50,6 +87,10 @@ func (sqlite3) DataTypeOf(field *StructField) string {
 			} else {
 				sqlType = "text"
 			}
+
+			if s.config.DefaultCollation != "" {
+				sqlType += " COLLATE " + s.config.DefaultCollation
+			}
 		case reflect.Struct:
 			if _, ok := dataValue.Interface().(time.Time); ok {
 				sqlType = "datetime"
@@ -73,7 +114,19 @@ func (sqlite3) DataTypeOf(field *StructField) string {
 
 func (s sqlite3) HasIndex(tableName string, indexName string) bool {
 	var count int
-	s.db.QueryRow(fmt.Sprintf("SELECT count(*) FROM sqlite_master WHERE tbl_name = ? AND sql LIKE '%%INDEX %v ON%%'", indexName), tableName).Scan(&count)
+	s.db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND name = ?", tableName, indexName).Scan(&count)
+	return count > 0
+}
+
+func (s sqlite3) HasForeignKey(tableName string, foreignKeyName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND tbl_name = ? AND sql LIKE ?", tableName, "%CONSTRAINT "+foreignKeyName+" FOREIGN KEY%").Scan(&count)
+	return count > 0
+}
+
+func (s sqlite3) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND tbl_name = ? AND sql LIKE ?", tableName, "%CONSTRAINT "+constraintName+" %").Scan(&count)
 	return count > 0
 }
 
@@ -106,3 +159,146 @@ func (s sqlite3) CurrentDatabase() (name string) {
 	}
 	return
 }
+
+func (s sqlite3) Tables() ([]string, error) {
+	return ScanStrings(s.db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"))
+}
+
+func (s sqlite3) ColumnTypes(tableName string) (columnTypes []ColumnType, err error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%v)", s.Quote(tableName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tableSQL string
+	s.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&tableSQL)
+	isAutoIncrement := strings.Contains(strings.ToUpper(tableSQL), "AUTOINCREMENT")
+
+	for rows.Next() {
+		var (
+			columnType   ColumnType
+			cid, pk      int
+			notNull      bool
+			declaredType string
+		)
+
+		if err = rows.Scan(&cid, &columnType.Name, &declaredType, &notNull, &columnType.Default, &pk); err != nil {
+			return nil, err
+		}
+
+		parseColumnType(&columnType, declaredType)
+		columnType.Nullable = !notNull && pk == 0
+		columnType.PrimaryKey = pk > 0
+		columnType.AutoIncrement = pk > 0 && isAutoIncrement && strings.EqualFold(columnType.DatabaseType, "integer")
+		columnTypes = append(columnTypes, columnType)
+	}
+
+	if err = rows.Err(); err == nil {
+		var indexes []TableIndex
+		if indexes, err = s.Indexes(tableName); err == nil {
+			MarkUniqueColumns(columnTypes, indexes)
+		}
+	}
+	return columnTypes, err
+}
+
+func (s sqlite3) Indexes(tableName string) (indexes []TableIndex, err error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA index_list(%v)", s.Quote(tableName)))
+	if err != nil {
+		return nil, err
+	}
+
+	// columns of index_list are different between sqlite versions, e.g. seq, name, unique, origin, partial
+	columns, _ := rows.Columns()
+	for rows.Next() {
+		var (
+			index  TableIndex
+			origin string
+			values = make([]interface{}, len(columns))
+		)
+
+		for idx, column := range columns {
+			switch column {
+			case "name":
+				values[idx] = &index.Name
+			case "unique":
+				values[idx] = &index.Unique
+			case "origin":
+				values[idx] = &origin
+			default:
+				values[idx] = new(interface{})
+			}
+		}
+
+		if err = rows.Scan(values...); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		index.Primary = origin == "pk"
+		indexes = append(indexes, index)
+	}
+	rows.Close()
+
+	for idx := range indexes {
+		if indexes[idx].Columns, err = ScanStrings(s.db.Query(fmt.Sprintf("SELECT name FROM pragma_index_info(%v) ORDER BY seqno", QuoteString(indexes[idx].Name)))); err != nil {
+			return nil, err
+		}
+	}
+
+	// integer primary key is an alias of rowid, which doesn't have an index
+	if !s.hasPrimaryIndex(indexes) {
+		var primaryKeys []string
+		if primaryKeys, err = ScanStrings(s.db.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%v) WHERE pk > 0 ORDER BY pk", QuoteString(tableName)))); err == nil && len(primaryKeys) > 0 {
+			indexes = append([]TableIndex{{Name: "PRIMARY", Columns: primaryKeys, Unique: true, Primary: true}}, indexes...)
+		}
+	}
+	return indexes, err
+}
+
+func (sqlite3) hasPrimaryIndex(indexes []TableIndex) bool {
+	for _, index := range indexes {
+		if index.Primary {
+			return true
+		}
+	}
+	return false
+}
+
+var sqliteForeignKeyRegexp = regexp.MustCompile("(?i)CONSTRAINT\\s+[\"`]?(\\w+)[\"`]?\\s+FOREIGN\\s+KEY\\s*\\(([^)]*)\\)")
+
+func (s sqlite3) ForeignKeys(tableName string) (foreignKeys []TableForeignKey, err error) {
+	// sqlite doesn't return constraint names, parse them from table's definition
+	var (
+		tableSQL string
+		names    = map[string]string{}
+	)
+	s.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", tableName).Scan(&tableSQL)
+	for _, matches := range sqliteForeignKeyRegexp.FindAllStringSubmatch(tableSQL, -1) {
+		names[strings.NewReplacer(`"`, "", "`", "", " ", "").Replace(matches[2])] = matches[1]
+	}
+
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA foreign_key_list(%v)", s.Quote(tableName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id, seq                                int
+			table, from, onUpdate, onDelete, match string
+			to                                     sql.NullString
+		)
+
+		if err = rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err != nil {
+			return nil, err
+		}
+		foreignKeys = AppendForeignKeyColumn(foreignKeys, fmt.Sprint(id), from, table, to.String, onDelete, onUpdate)
+	}
+
+	for idx := range foreignKeys {
+		foreignKeys[idx].Name = names[strings.Join(foreignKeys[idx].Columns, ",")]
+	}
+	return foreignKeys, rows.Err()
+}
@@ -0,0 +1,14 @@
+// Package cockroach registers postgres driver as `cockroach`, CockroachDB speaks postgres wire protocol
+//     import _ "github.com/nkovacs/gorm/dialects/cockroach"
+//     db, err := gorm.Open("cockroach", "postgresql://root@localhost:26257/gorm?sslmode=disable")
+package cockroach
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+func init() {
+	sql.Register("cockroach", &pq.Driver{})
+}
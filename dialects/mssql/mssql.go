@@ -12,19 +12,54 @@ import (
 	"github.com/nkovacs/gorm"
 )
 
+// setIdentityInsert allow inserting explicit value into identity column if primary key is set,
+// only one table could have IDENTITY_INSERT ON in a session, so it is turned off after created
 func setIdentityInsert(scope *gorm.Scope) {
-	if scope.Dialect().GetName() == "mssql" {
-		scope.NewDB().Exec(fmt.Sprintf("SET IDENTITY_INSERT %v ON", scope.TableName()))
+	if !scope.Dialect().Capabilities().IdentityInsert {
+		return
+	}
+
+	// tables without identity column, e.g. with string or composite primary keys, don't allow IDENTITY_INSERT
+	if primaryFields := scope.PrimaryFields(); len(primaryFields) == 1 && !primaryFields[0].IsBlank && isIdentityField(primaryFields[0].StructField) {
+		if scope.Err(scope.NewDB().Exec(fmt.Sprintf("SET IDENTITY_INSERT %v ON", scope.QuotedTableName())).Error) == nil {
+			scope.InstanceSet("mssql:identity_insert_on", true)
+		}
+	}
+}
+
+// isIdentityField check if field is created as IDENTITY column, which are integer primary keys and integer fields tagged with `AUTO_INCREMENT`,
+// unless they are tagged with `AUTO_INCREMENT:false` or have custom type
+func isIdentityField(field *gorm.StructField) bool {
+	dataValue, sqlType, _, _ := gorm.ParseFieldStructForDialect(field)
+	if sqlType != "" {
+		return false
+	}
+
+	switch dataValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value, ok := field.TagSettings["AUTO_INCREMENT"]; ok {
+			return value != "FALSE"
+		}
+		return field.IsPrimaryKey
+	}
+	return false
+}
+
+func unsetIdentityInsert(scope *gorm.Scope) {
+	if _, ok := scope.InstanceGet("mssql:identity_insert_on"); ok {
+		scope.NewDB().Exec(fmt.Sprintf("SET IDENTITY_INSERT %v OFF", scope.QuotedTableName()))
 	}
 }
 
 func init() {
 	gorm.DefaultCallback.Create().After("gorm:begin_transaction").Register("mssql:set_identity_insert", setIdentityInsert)
+	gorm.DefaultCallback.Create().After("gorm:create").Register("mssql:unset_identity_insert", unsetIdentityInsert)
 	gorm.RegisterDialect("mssql", &mssql{})
 }
 
 type mssql struct {
-	db *sql.DB
+	db     *sql.DB
+	config gorm.DialectConfig
 	gorm.DefaultForeignKeyNamer
 }
 
@@ -36,35 +71,51 @@ func (s *mssql) SetDB(db *sql.DB) {
 	s.db = db
 }
 
+func (s *mssql) SetConfig(config gorm.DialectConfig) {
+	s.config = config
+}
+
 func (mssql) BindVar(i int) string {
 	return "$$" // ?
 }
 
+func (mssql) Capabilities() gorm.Capabilities {
+	return gorm.Capabilities{
+		TransactionalDDL:       true,
+		AlterColumn:            gorm.AlterColumnPlain,
+		AlterConstraints:       true,
+		DefaultValues:          true,
+		MaxBindVars:            2100,
+		TrueLiteral:            "1",
+		FalseLiteral:           "0",
+		PartialIndexes:         true,
+		AutoIncrement:          gorm.AutoIncrementKey,
+		OrderRequiredForOffset: true,
+		IdentityInsert:         true,
+		WindowFunctions:        true,
+	}
+}
+
 func (mssql) Quote(key string) string {
 	return fmt.Sprintf(`"%s"`, key)
 }
 
-func (mssql) DataTypeOf(field *gorm.StructField) string {
+func (s mssql) DataTypeOf(field *gorm.StructField) string {
 	var dataValue, sqlType, size, additionalType = gorm.ParseFieldStructForDialect(field)
-
-    forceNoIncrement := false
-    if v, ok := field.TagSettings["AUTO_INCREMENT"]; ok && v == "FALSE" {
-        delete(field.TagSettings, "AUTO_INCREMENT")
-        forceNoIncrement = true
-    }
+	size = s.config.StringSize(field, size)
 
 	if sqlType == "" {
 		switch dataValue.Kind() {
 		case reflect.Bool:
 			sqlType = "bit"
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uintptr:
-			if _, ok := field.TagSettings["AUTO_INCREMENT"]; ok || !forceNoIncrement && field.IsPrimaryKey {
+			if isIdentityField(field) {
 				sqlType = "int IDENTITY(1,1)"
 			} else {
 				sqlType = "int"
 			}
 		case reflect.Int64, reflect.Uint64:
-			if _, ok := field.TagSettings["AUTO_INCREMENT"]; ok || !forceNoIncrement && field.IsPrimaryKey {
+			if isIdentityField(field) {
 				sqlType = "bigint IDENTITY(1,1)"
 			} else {
 				sqlType = "bigint"
@@ -77,6 +128,10 @@ func (mssql) DataTypeOf(field *gorm.StructField) string {
 			} else {
 				sqlType = "text"
 			}
+
+			if s.config.DefaultCollation != "" {
+				sqlType += " COLLATE " + s.config.DefaultCollation
+			}
 		case reflect.Struct:
 			if _, ok := dataValue.Interface().(time.Time); ok {
 				sqlType = "datetime2"
@@ -114,7 +169,15 @@ func (s mssql) RemoveIndex(tableName string, indexName string) error {
 }
 
 func (s mssql) HasForeignKey(tableName string, foreignKeyName string) bool {
-	return false
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM sys.foreign_keys WHERE name = ? AND parent_object_id = OBJECT_ID(?)", foreignKeyName, tableName).Scan(&count)
+	return count > 0
+}
+
+func (s mssql) HasConstraint(tableName string, constraintName string) bool {
+	var count int
+	s.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE table_catalog = ? AND table_name = ? AND constraint_name = ?", s.CurrentDatabase(), tableName, constraintName).Scan(&count)
+	return count > 0
 }
 
 func (s mssql) HasTable(tableName string) bool {
@@ -129,20 +192,156 @@ func (s mssql) HasColumn(tableName string, columnName string) bool {
 	return count > 0
 }
 
+func (s mssql) Comment(tableName string, columnName string) string {
+	var comment sql.NullString
+	if columnName == "" {
+		s.db.QueryRow("SELECT CAST(value AS nvarchar(max)) FROM sys.extended_properties WHERE major_id = OBJECT_ID(?) AND minor_id = 0 AND name = 'MS_Description'", tableName).Scan(&comment)
+	} else {
+		s.db.QueryRow("SELECT CAST(value AS nvarchar(max)) FROM sys.extended_properties WHERE major_id = OBJECT_ID(?) AND minor_id = COLUMNPROPERTY(OBJECT_ID(?), ?, 'ColumnId') AND name = 'MS_Description'", tableName, tableName, columnName).Scan(&comment)
+	}
+	return comment.String
+}
+
+// CommentSQL set comment with extended property `MS_Description`, which need to be updated if it exists already
+func (s mssql) CommentSQL(tableName string, field *gorm.StructField, comment string) string {
+	var (
+		procedure = "sp_addextendedproperty"
+		column    string
+	)
+
+	if field == nil {
+		if s.Comment(tableName, "") != "" {
+			procedure = "sp_updateextendedproperty"
+		}
+	} else {
+		if s.Comment(tableName, field.DBName) != "" {
+			procedure = "sp_updateextendedproperty"
+		}
+		column = fmt.Sprintf(", @level2type = N'COLUMN', @level2name = N%v", gorm.QuoteString(field.DBName))
+	}
+
+	return fmt.Sprintf("DECLARE @schema sysname = SCHEMA_NAME(); EXEC %v @name = N'MS_Description', @value = N%v, @level0type = N'SCHEMA', @level0name = @schema, @level1type = N'TABLE', @level1name = N%v%v",
+		procedure, gorm.QuoteString(comment), gorm.QuoteString(tableName), column)
+}
+
+func (s mssql) Tables() ([]string, error) {
+	return gorm.ScanStrings(s.db.Query("SELECT table_name FROM INFORMATION_SCHEMA.tables WHERE table_catalog = ? AND table_type = 'BASE TABLE' ORDER BY table_name", s.CurrentDatabase()))
+}
+
+func (s mssql) ColumnTypes(tableName string) (columnTypes []gorm.ColumnType, err error) {
+	rows, err := s.db.Query(`SELECT column_name, data_type, character_maximum_length, numeric_precision, numeric_scale, is_nullable, column_default, COLUMNPROPERTY(OBJECT_ID(table_name), column_name, 'IsIdentity')
+		FROM INFORMATION_SCHEMA.columns WHERE table_catalog = ? AND table_name = ? ORDER BY ordinal_position`, s.CurrentDatabase(), tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			columnType               gorm.ColumnType
+			length, precision, scale sql.NullInt64
+			nullable                 string
+			identity                 sql.NullInt64
+		)
+
+		if err = rows.Scan(&columnType.Name, &columnType.DatabaseType, &length, &precision, &scale, &nullable, &columnType.Default, &identity); err != nil {
+			return nil, err
+		}
+
+		columnType.Length, columnType.Precision, columnType.Scale = length.Int64, precision.Int64, scale.Int64
+		columnType.Nullable = nullable == "YES"
+		columnType.AutoIncrement = identity.Int64 == 1
+		columnType.Comment = s.Comment(tableName, columnType.Name)
+		columnTypes = append(columnTypes, columnType)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	indexes, err := s.Indexes(tableName)
+	if err != nil {
+		return nil, err
+	}
+	gorm.MarkUniqueColumns(columnTypes, indexes)
+	return columnTypes, nil
+}
+
+func (s mssql) Indexes(tableName string) (indexes []gorm.TableIndex, err error) {
+	rows, err := s.db.Query(`SELECT i.name, c.name, i.is_unique, i.is_primary_key
+		FROM sys.indexes i
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE i.object_id = OBJECT_ID(?) AND i.name IS NOT NULL
+		ORDER BY i.name, ic.key_ordinal`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, column    string
+			unique, primary bool
+		)
+
+		if err = rows.Scan(&name, &column, &unique, &primary); err != nil {
+			return nil, err
+		}
+
+		indexes = gorm.AppendIndexColumn(indexes, name, column, unique, primary)
+	}
+	return indexes, rows.Err()
+}
+
+func (s mssql) ForeignKeys(tableName string) (foreignKeys []gorm.TableForeignKey, err error) {
+	rows, err := s.db.Query(`SELECT fk.name, pc.name, OBJECT_NAME(fk.referenced_object_id), rc.name, fk.delete_referential_action_desc, fk.update_referential_action_desc
+		FROM sys.foreign_keys fk
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+		JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE fk.parent_object_id = OBJECT_ID(?)
+		ORDER BY fk.name, fkc.constraint_column_id`, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, column, referencedTable, referencedColumn, onDelete, onUpdate string
+		if err = rows.Scan(&name, &column, &referencedTable, &referencedColumn, &onDelete, &onUpdate); err != nil {
+			return nil, err
+		}
+
+		// sys.foreign_keys describes actions like `NO_ACTION`, `SET_NULL`
+		foreignKeys = gorm.AppendForeignKeyColumn(foreignKeys, name, column, referencedTable, referencedColumn, strings.Replace(onDelete, "_", " ", -1), strings.Replace(onUpdate, "_", " ", -1))
+	}
+	return foreignKeys, rows.Err()
+}
+
 func (s mssql) CurrentDatabase() (name string) {
 	s.db.QueryRow("SELECT DB_NAME() AS [Current Database]").Scan(&name)
 	return
 }
 
+// LimitAndOffsetSQL OFFSET is required before FETCH NEXT, query should be ordered, which is ordered by primary key if no orders
 func (mssql) LimitAndOffsetSQL(limit, offset interface{}) (sql string) {
+	var parsedLimit, parsedOffset int64
 	if limit != nil {
-		if parsedLimit, err := strconv.ParseInt(fmt.Sprint(limit), 0, 0); err == nil && parsedLimit > 0 {
-			sql += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", parsedLimit)
+		if parsed, err := strconv.ParseInt(fmt.Sprint(limit), 0, 0); err == nil && parsed > 0 {
+			parsedLimit = parsed
 		}
 	}
 	if offset != nil {
-		if parsedOffset, err := strconv.ParseInt(fmt.Sprint(offset), 0, 0); err == nil && parsedOffset > 0 {
-			sql += fmt.Sprintf(" OFFSET %d ROWS", parsedOffset)
+		if parsed, err := strconv.ParseInt(fmt.Sprint(offset), 0, 0); err == nil && parsed > 0 {
+			parsedOffset = parsed
+		}
+	}
+
+	if parsedLimit > 0 || parsedOffset > 0 {
+		sql += fmt.Sprintf(" OFFSET %d ROWS", parsedOffset)
+		if parsedLimit > 0 {
+			sql += fmt.Sprintf(" FETCH NEXT %d ROWS ONLY", parsedLimit)
 		}
 	}
 	return
@@ -155,3 +354,7 @@ func (mssql) SelectFromDummyTable() string {
 func (mssql) LastInsertIDReturningSuffix(tableName, columnName string) string {
 	return ""
 }
+
+func (mssql) LastInsertIDOutputInterstitial(tableName, columnName string) string {
+	return fmt.Sprintf("OUTPUT INSERTED.%v", columnName)
+}
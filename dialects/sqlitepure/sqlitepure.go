@@ -0,0 +1,25 @@
+// Package sqlitepure registers pure Go sqlite driver as `sqlite-pure`, it doesn't need cgo, so could be used in static builds and cross compiles,
+// dialect of sqlite3 is used with it
+//     import _ "github.com/nkovacs/gorm/dialects/sqlitepure"
+//     db, err := gorm.Open("sqlite-pure", "/tmp/gorm.db")
+package sqlitepure
+
+import (
+	"database/sql"
+
+	"github.com/nkovacs/gorm"
+	_ "modernc.org/sqlite"
+)
+
+func init() {
+	// `sqlite` is registered by modernc.org/sqlite, sql.Open doesn't connect, it is only used to get the driver
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sql.Register("sqlite-pure", db.Driver())
+	db.Close()
+
+	dialect, _ := gorm.GetDialect("sqlite3")
+	gorm.RegisterDialect("sqlite-pure", dialect)
+}
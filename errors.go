@@ -16,6 +16,8 @@ var (
 	ErrCantStartTransaction = errors.New("can't start transaction")
 	// ErrUnaddressable unaddressable value
 	ErrUnaddressable = errors.New("using unaddressable value")
+	// ErrMissingWhereClause deleting records without conditions, which would delete all records of the table
+	ErrMissingWhereClause = errors.New("missing WHERE clause")
 )
 
 type errorsInterface interface {
@@ -0,0 +1,175 @@
+// Package field typed columns used to build query conditions, they are usually generated by `gen.Generator.GenerateQuery`, for example:
+//     db.Where(q.User.Name.Eq("jinzhu")).Order(q.User.Age.Desc()).Find(&users)
+// columns are quoted with the dialect of the DB when building SQL
+package field
+
+import (
+	"time"
+
+	"github.com/nkovacs/gorm"
+)
+
+type column struct {
+	table string
+	name  string
+}
+
+// Column column qualified with table name, could be used with `Select`, `Pluck`, `Order`,
+// its table is replaced with the one set with `Table` when querying the model of the table
+func (c column) Column() *gorm.SQLExpr {
+	return gorm.Expr("?", c.ref())
+}
+
+// IsNull column is NULL
+func (c column) IsNull() *gorm.SQLExpr {
+	return gorm.Expr("? IS NULL", c.ref())
+}
+
+// IsNotNull column is not NULL
+func (c column) IsNotNull() *gorm.SQLExpr {
+	return gorm.Expr("? IS NOT NULL", c.ref())
+}
+
+// Asc order by column ascending, used with `Order`
+func (c column) Asc() *gorm.SQLExpr {
+	return gorm.Expr("? ASC", c.ref())
+}
+
+// Desc order by column descending, used with `Order`
+func (c column) Desc() *gorm.SQLExpr {
+	return gorm.Expr("? DESC", c.ref())
+}
+
+func (c column) ref() gorm.Column {
+	return gorm.Column{Table: c.table, Name: c.name}
+}
+
+func (c column) compare(operator string, value interface{}) *gorm.SQLExpr {
+	return gorm.Expr("? "+operator+" ?", c.ref(), value)
+}
+
+// ordered column compared with values of type T
+type ordered[T any] struct{ column }
+
+// Eq column = value
+func (f ordered[T]) Eq(value T) *gorm.SQLExpr {
+	return f.compare("=", value)
+}
+
+// Neq column <> value
+func (f ordered[T]) Neq(value T) *gorm.SQLExpr {
+	return f.compare("<>", value)
+}
+
+// Gt column > value
+func (f ordered[T]) Gt(value T) *gorm.SQLExpr {
+	return f.compare(">", value)
+}
+
+// Gte column >= value
+func (f ordered[T]) Gte(value T) *gorm.SQLExpr {
+	return f.compare(">=", value)
+}
+
+// Lt column < value
+func (f ordered[T]) Lt(value T) *gorm.SQLExpr {
+	return f.compare("<", value)
+}
+
+// Lte column <= value
+func (f ordered[T]) Lte(value T) *gorm.SQLExpr {
+	return f.compare("<=", value)
+}
+
+// Between column BETWEEN left AND right
+func (f ordered[T]) Between(left, right T) *gorm.SQLExpr {
+	return gorm.Expr("? BETWEEN ? AND ?", f.ref(), left, right)
+}
+
+// In column IN (values)
+func (f ordered[T]) In(values ...T) *gorm.SQLExpr {
+	return gorm.Expr("? IN (?)", f.ref(), values)
+}
+
+// NotIn column NOT IN (values)
+func (f ordered[T]) NotIn(values ...T) *gorm.SQLExpr {
+	return gorm.Expr("? NOT IN (?)", f.ref(), values)
+}
+
+// Field untyped column, values are not type checked, used for columns with custom types
+type Field struct{ ordered[interface{}] }
+
+// NewField initialize untyped column of table
+func NewField(table, name string) Field {
+	return Field{ordered[interface{}]{column{table: table, name: name}}}
+}
+
+// Like column LIKE pattern
+func (f Field) Like(pattern string) *gorm.SQLExpr {
+	return f.compare("LIKE", pattern)
+}
+
+// NotLike column NOT LIKE pattern
+func (f Field) NotLike(pattern string) *gorm.SQLExpr {
+	return f.compare("NOT LIKE", pattern)
+}
+
+// String string column
+type String struct{ ordered[string] }
+
+// NewString initialize string column of table
+func NewString(table, name string) String {
+	return String{ordered[string]{column{table: table, name: name}}}
+}
+
+// Like column LIKE pattern
+func (f String) Like(pattern string) *gorm.SQLExpr {
+	return f.compare("LIKE", pattern)
+}
+
+// NotLike column NOT LIKE pattern
+func (f String) NotLike(pattern string) *gorm.SQLExpr {
+	return f.compare("NOT LIKE", pattern)
+}
+
+// Bool bool column
+type Bool struct{ column }
+
+// NewBool initialize bool column of table
+func NewBool(table, name string) Bool {
+	return Bool{column{table: table, name: name}}
+}
+
+// Eq column = value
+func (f Bool) Eq(value bool) *gorm.SQLExpr {
+	return f.compare("=", value)
+}
+
+// Neq column <> value
+func (f Bool) Neq(value bool) *gorm.SQLExpr {
+	return f.compare("<>", value)
+}
+
+// Numeric types of numeric columns
+type Numeric interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Number numeric column, values have the type of model's field, e.g. `Number[int64]`
+type Number[T Numeric] struct{ ordered[T] }
+
+// NewNumber initialize numeric column of table
+//     Age: field.NewNumber[uint8]("users", "age")
+func NewNumber[T Numeric](table, name string) Number[T] {
+	return Number[T]{ordered[T]{column{table: table, name: name}}}
+}
+
+// Time time column
+type Time struct{ ordered[time.Time] }
+
+// NewTime initialize time column of table
+func NewTime(table, name string) Time {
+	return Time{ordered[time.Time]{column{table: table, name: name}}}
+}
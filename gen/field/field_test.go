@@ -0,0 +1,122 @@
+package field_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nkovacs/gorm"
+	_ "github.com/nkovacs/gorm/dialects/sqlite"
+	"github.com/nkovacs/gorm/gen/field"
+)
+
+type Product struct {
+	ID        uint
+	Code      string
+	Price     float64
+	Stock     int64
+	ExpiredAt *time.Time
+}
+
+var product = struct {
+	ID        field.Number[uint]
+	Code      field.String
+	Price     field.Number[float64]
+	Stock     field.Number[int64]
+	ExpiredAt field.Time
+}{
+	ID:        field.NewNumber[uint]("products", "id"),
+	Code:      field.NewString("products", "code"),
+	Price:     field.NewNumber[float64]("products", "price"),
+	Stock:     field.NewNumber[int64]("products", "stock"),
+	ExpiredAt: field.NewTime("products", "expired_at"),
+}
+
+func TestConditions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorm-field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := gorm.Open("sqlite3", filepath.Join(dir, "field.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	db.AutoMigrate(&Product{})
+	db.Create(&Product{Code: "A1", Price: 10, Stock: 1})
+	db.Create(&Product{Code: "A2", Price: 20, Stock: 0, ExpiredAt: &now})
+	db.Create(&Product{Code: "B1", Price: 30, Stock: 5})
+
+	for name, test := range map[string]struct {
+		conditions []*gorm.SQLExpr
+		codes      string
+	}{
+		"eq":      {[]*gorm.SQLExpr{product.Code.Eq("A1")}, "A1"},
+		"neq":     {[]*gorm.SQLExpr{product.Code.Neq("A1")}, "A2,B1"},
+		"like":    {[]*gorm.SQLExpr{product.Code.Like("A%")}, "A1,A2"},
+		"not in":  {[]*gorm.SQLExpr{product.Code.NotIn("A1", "B1")}, "A2"},
+		"in":      {[]*gorm.SQLExpr{product.Code.In("A1", "B1")}, "A1,B1"},
+		"empty":   {[]*gorm.SQLExpr{product.Code.In()}, ""},
+		"gt":      {[]*gorm.SQLExpr{product.Price.Gt(10), product.Stock.Lte(1)}, "A2"},
+		"between": {[]*gorm.SQLExpr{product.Price.Between(15, 35)}, "A2,B1"},
+		"null":    {[]*gorm.SQLExpr{product.ExpiredAt.IsNull()}, "A1,B1"},
+		"time":    {[]*gorm.SQLExpr{product.ExpiredAt.Lte(now.Add(time.Minute))}, "A2"},
+	} {
+		query := db.Model(&Product{})
+		for _, condition := range test.conditions {
+			query = query.Where(condition)
+		}
+
+		var codes []string
+		if err := query.Order(product.Code.Asc()).Pluck(product.Code.Column(), &codes).Error; err != nil {
+			t.Errorf("%v: no error should happen when query with typed conditions, but got %+v", name, err)
+		}
+
+		if got := strings.Join(codes, ","); got != test.codes {
+			t.Errorf("%v: should find %v, but got %v", name, test.codes, got)
+		}
+	}
+}
+
+type Keyword struct {
+	ID    uint
+	Order int64
+}
+
+func TestQuotedColumnsWithTable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gorm-field")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := gorm.Open("sqlite3", filepath.Join(dir, "field.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	order := field.NewNumber[int64]("keywords", "order")
+	db.AutoMigrate(&Keyword{})
+	db.Table("archived_keywords").AutoMigrate(&Keyword{})
+	db.Create(&Keyword{Order: 1})
+	db.Table("archived_keywords").Create(&Keyword{Order: 2})
+	db.Table("archived_keywords").Create(&Keyword{Order: 3})
+
+	var orders []int64
+	if err := db.Model(&Keyword{}).Where(order.Gte(1)).Order(order.Desc()).Pluck(order.Column(), &orders).Error; err != nil || len(orders) != 1 || orders[0] != 1 {
+		t.Errorf("Reserved word column should be quoted, but got %v, %+v", orders, err)
+	}
+
+	orders = nil
+	if err := db.Table("archived_keywords").Model(&Keyword{}).Where(order.Gte(1)).Order(order.Desc()).Pluck(order.Column(), &orders).Error; err != nil || len(orders) != 2 || orders[0] != 3 {
+		t.Errorf("Column should be qualified with table set with Table, but got %v, %+v", orders, err)
+	}
+}
@@ -0,0 +1,411 @@
+// Package gen generate gorm models from an existing database schema, for example:
+//     db, _ := gorm.Open("sqlite3", "legacy.db")
+//     source, err := gen.New(db, "models").Generate()
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"github.com/nkovacs/gorm"
+)
+
+// Generator generate model structs from tables read with dialect's introspection API
+type Generator struct {
+	DB      *gorm.DB
+	Package string
+}
+
+// Model struct generated for a table
+type Model struct {
+	Name      string
+	TableName string
+	Fields    []*Field
+}
+
+// Field struct field generated for a column or relationship
+type Field struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+type table struct {
+	name        string
+	columns     []gorm.ColumnType
+	indexes     []gorm.TableIndex
+	foreignKeys []gorm.TableForeignKey
+}
+
+// New initialize a generator writing models to package `packageName`
+func New(db *gorm.DB, packageName string) *Generator {
+	return &Generator{DB: db, Package: packageName}
+}
+
+// Models read schema of tables and build models for them, all tables will be used if no table names given
+func (g *Generator) Models(tableNames ...string) ([]*Model, error) {
+	dialect, ok := g.DB.Dialect().(gorm.IntrospectionDialect)
+	if !ok {
+		return nil, fmt.Errorf("dialect %v doesn't support reading schema of database", g.DB.Dialect().GetName())
+	}
+
+	allTableNames, err := dialect.Tables()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tableNames) == 0 {
+		tableNames = allTableNames
+	}
+
+	var (
+		tables   = map[string]*table{}
+		selected = map[string]bool{}
+	)
+
+	for _, tableName := range tableNames {
+		selected[tableName] = true
+	}
+
+	// foreign keys of all tables are needed to find has many relations of selected tables
+	for _, tableName := range allTableNames {
+		t := &table{name: tableName}
+		if t.foreignKeys, err = dialect.ForeignKeys(tableName); err != nil {
+			return nil, err
+		}
+
+		if selected[tableName] {
+			if t.columns, err = dialect.ColumnTypes(tableName); err != nil {
+				return nil, err
+			}
+			if t.indexes, err = dialect.Indexes(tableName); err != nil {
+				return nil, err
+			}
+		}
+		tables[tableName] = t
+	}
+
+	var models []*Model
+	for _, tableName := range tableNames {
+		t, ok := tables[tableName]
+		if !ok {
+			return nil, fmt.Errorf("table %v not found", tableName)
+		}
+		models = append(models, g.buildModel(t, tables, selected))
+	}
+	return models, nil
+}
+
+// Generate generate gofmt-ed source of models, all tables will be used if no table names given
+func (g *Generator) Generate(tableNames ...string) ([]byte, error) {
+	models, err := g.Models(tableNames...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		body      bytes.Buffer
+		needsTime bool
+	)
+
+	for _, model := range models {
+		fmt.Fprintf(&body, "\n// %v model for table `%v`\n", model.Name, model.TableName)
+		fmt.Fprintf(&body, "type %v struct {\n", model.Name)
+		for _, field := range model.Fields {
+			fmt.Fprintf(&body, "\t%v %v %v\n", field.Name, field.Type, structTag("gorm:"+strconv.Quote(field.Tag)))
+			needsTime = needsTime || strings.HasSuffix(field.Type, "time.Time")
+		}
+		fmt.Fprintf(&body, "}\n\n")
+		fmt.Fprintf(&body, "// TableName table name of %v\n", model.Name)
+		fmt.Fprintf(&body, "func (%v) TableName() string {\n\treturn %q\n}\n", model.Name, model.TableName)
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by gorm-gen. DO NOT EDIT.\n\npackage %v\n", g.Package)
+	if needsTime {
+		fmt.Fprintf(&source, "\nimport \"time\"\n")
+	}
+	source.Write(body.Bytes())
+
+	return format.Source(source.Bytes())
+}
+
+func (g *Generator) buildModel(t *table, tables map[string]*table, selected map[string]bool) *Model {
+	var (
+		model                    = &Model{Name: modelName(t.name), TableName: t.name}
+		columnFields, fieldNames = columnFieldNames(t)
+		columnTags               = map[string][]string{}
+	)
+
+	for _, index := range t.indexes {
+		if index.Primary || strings.HasPrefix(index.Name, "sqlite_autoindex_") {
+			continue
+		}
+
+		key := "index"
+		if index.Unique {
+			key = "unique_index"
+		}
+
+		for idx, column := range index.Columns {
+			setting := index.Name
+			if len(index.Columns) > 1 {
+				setting += fmt.Sprintf(",priority:%v", idx+1)
+			}
+			columnTags[column+":"+key] = append(columnTags[column+":"+key], setting)
+		}
+	}
+
+	for _, column := range t.columns {
+		field := &Field{Name: columnFields[column.Name], Type: goType(column)}
+		tags := []string{"column:" + column.Name}
+		if column.PrimaryKey {
+			tags = append(tags, "primary_key")
+		}
+
+		if !column.AutoIncrement {
+			if column.Length > 0 && strings.TrimPrefix(field.Type, "*") == "string" {
+				tags = append(tags, fmt.Sprintf("size:%v", column.Length))
+			} else {
+				tags = append(tags, "type:"+columnTypeOf(column))
+			}
+		}
+
+		if !column.Nullable && !column.PrimaryKey {
+			tags = append(tags, "not null")
+		}
+
+		// settings are separated by `;` in struct tags, values containing it couldn't be kept
+		if column.Default.Valid && !column.AutoIncrement && !strings.Contains(column.Default.String, ";") {
+			tags = append(tags, "default:"+column.Default.String)
+		}
+
+		if column.Comment != "" && !strings.Contains(column.Comment, ";") {
+			tags = append(tags, "comment:"+column.Comment)
+		}
+
+		for _, key := range []string{"index", "unique_index"} {
+			if settings := columnTags[column.Name+":"+key]; len(settings) > 0 {
+				tags = append(tags, key+":"+strings.Join(settings, ","))
+			}
+		}
+
+		if column.Unique && len(columnTags[column.Name+":unique_index"]) == 0 {
+			tags = append(tags, "unique")
+		}
+
+		field.Tag = strings.Join(tags, ";")
+		model.Fields = append(model.Fields, field)
+	}
+
+	// belongs to relations, defined by foreign keys of current table
+	for _, foreignKey := range t.foreignKeys {
+		if len(foreignKey.Columns) != 1 || !selected[foreignKey.ReferencedTable] {
+			continue
+		}
+
+		referencedModel := modelName(foreignKey.ReferencedTable)
+		name := uniqueName(fieldNames, gorm.ToFieldName(strings.TrimSuffix(foreignKey.Columns[0], "_id")), referencedModel)
+		fieldNames[name] = true
+
+		tags := []string{"foreignkey:" + columnFields[foreignKey.Columns[0]]}
+		if referencedTable := tables[foreignKey.ReferencedTable]; !isPrimaryKey(referencedTable, foreignKey.ReferencedColumns[0]) {
+			tags = append(tags, "association_foreignkey:"+fieldNameOf(referencedTable, foreignKey.ReferencedColumns[0]))
+		}
+
+		var rules []string
+		if onDelete := foreignKey.OnDelete; onDelete != "" && onDelete != "NO ACTION" {
+			rules = append(rules, "OnDelete:"+onDelete)
+		}
+		if onUpdate := foreignKey.OnUpdate; onUpdate != "" && onUpdate != "NO ACTION" {
+			rules = append(rules, "OnUpdate:"+onUpdate)
+		}
+		if len(rules) > 0 {
+			tags = append(tags, "constraint:"+strings.Join(rules, ","))
+		}
+
+		model.Fields = append(model.Fields, &Field{Name: name, Type: "*" + referencedModel, Tag: strings.Join(tags, ";")})
+	}
+
+	// has one or has many relations, defined by foreign keys of other tables referencing current table, it is has one if the foreign key is unique
+	var childTableNames []string
+	for tableName := range tables {
+		childTableNames = append(childTableNames, tableName)
+	}
+	sort.Strings(childTableNames)
+
+	for _, childTableName := range childTableNames {
+		if !selected[childTableName] {
+			continue
+		}
+
+		for _, foreignKey := range tables[childTableName].foreignKeys {
+			if len(foreignKey.Columns) != 1 || foreignKey.ReferencedTable != t.name {
+				continue
+			}
+
+			childName, childType := gorm.ToFieldName(childTableName), "[]"+modelName(childTableName)
+			if isUnique(tables[childTableName], foreignKey.Columns[0]) {
+				childName, childType = modelName(childTableName), "*"+modelName(childTableName)
+			}
+			name := uniqueName(fieldNames, childName, childName+"By"+gorm.ToFieldName(strings.TrimSuffix(foreignKey.Columns[0], "_id")))
+			fieldNames[name] = true
+
+			tags := []string{"foreignkey:" + fieldNameOf(tables[childTableName], foreignKey.Columns[0])}
+			if !isPrimaryKey(t, foreignKey.ReferencedColumns[0]) {
+				tags = append(tags, "association_foreignkey:"+columnFields[foreignKey.ReferencedColumns[0]])
+			}
+
+			model.Fields = append(model.Fields, &Field{Name: name, Type: childType, Tag: strings.Join(tags, ";")})
+		}
+	}
+
+	return model
+}
+
+// columnFieldNames struct field names of table's columns, names which are invalid or used already get a `Column` prefix
+func columnFieldNames(t *table) (columnFields map[string]string, fieldNames map[string]bool) {
+	columnFields, fieldNames = map[string]string{}, map[string]bool{}
+	for _, column := range t.columns {
+		name := gorm.ToFieldName(column.Name)
+		if name == "" || fieldNames[name] {
+			name = uniqueName(fieldNames, "Column"+name)
+		}
+		columnFields[column.Name], fieldNames[name] = name, true
+	}
+	return
+}
+
+// fieldNameOf struct field name generated for column, columns are only read for selected tables
+func fieldNameOf(t *table, columnName string) string {
+	columnFields, _ := columnFieldNames(t)
+	if name, ok := columnFields[columnName]; ok {
+		return name
+	}
+	return gorm.ToFieldName(columnName)
+}
+
+// structTag struct tag literal, it is a raw string unless the tag contains backticks
+func structTag(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
+
+// modelName struct name for table, e.g: `user_languages` => `UserLanguage`
+func modelName(tableName string) string {
+	return gorm.ToFieldName(inflection.Singular(tableName))
+}
+
+// uniqueName return first candidate not used yet, or last candidate with a number suffix
+func uniqueName(used map[string]bool, candidates ...string) string {
+	for _, candidate := range candidates {
+		if candidate != "" && !used[candidate] {
+			return candidate
+		}
+	}
+
+	last := candidates[len(candidates)-1]
+	for i := 2; ; i++ {
+		if name := fmt.Sprintf("%v%v", last, i); !used[name] {
+			return name
+		}
+	}
+}
+
+func isPrimaryKey(t *table, columnName string) bool {
+	for _, column := range t.columns {
+		if column.Name == columnName {
+			return column.PrimaryKey
+		}
+	}
+	// columns are only read for selected tables, `id` is the default primary key
+	return len(t.columns) == 0 && columnName == "id"
+}
+
+// isUnique check if column is unique by itself, with an unique index or as the only primary key
+func isUnique(t *table, columnName string) bool {
+	var primaryKeys int
+	for _, column := range t.columns {
+		if column.PrimaryKey {
+			primaryKeys++
+		}
+	}
+
+	for _, column := range t.columns {
+		if column.Name == columnName {
+			return column.Unique || (column.PrimaryKey && primaryKeys == 1)
+		}
+	}
+	return false
+}
+
+// columnTypeOf declared column type, e.g: `decimal(10,2)`
+func columnTypeOf(column gorm.ColumnType) string {
+	if column.Precision > 0 && column.Scale > 0 {
+		return fmt.Sprintf("%v(%v,%v)", column.DatabaseType, column.Precision, column.Scale)
+	}
+	if column.Length > 0 {
+		return fmt.Sprintf("%v(%v)", column.DatabaseType, column.Length)
+	}
+	return column.DatabaseType
+}
+
+// goType go type for column, nullable columns use pointer like `DeletedAt`
+func goType(column gorm.ColumnType) string {
+	typ := baseGoType(column)
+	if column.Nullable && !column.PrimaryKey && typ != "[]byte" {
+		return "*" + typ
+	}
+	return typ
+}
+
+// baseGoType match whole type name, e.g: `double precision`, `timestamp with time zone` or `int unsigned`, arrays and unknown types are scanned as string
+func baseGoType(column gorm.ColumnType) string {
+	databaseType := strings.ToLower(strings.TrimSpace(column.DatabaseType))
+	if strings.HasSuffix(databaseType, "]") || databaseType == "array" {
+		return "string"
+	}
+
+	var params string
+	if start, end := strings.Index(databaseType, "("), strings.Index(databaseType, ")"); start >= 0 && end > start {
+		params = strings.TrimSpace(databaseType[start+1 : end])
+		databaseType = databaseType[:start] + " " + databaseType[end+1:]
+	}
+
+	names := strings.Fields(databaseType)
+	if len(names) == 0 {
+		return "string"
+	}
+
+	switch names[0] {
+	case "bool", "boolean":
+		return "bool"
+	case "bit":
+		if params == "" || params == "1" {
+			return "bool"
+		}
+		return "[]byte"
+	case "tinyint":
+		// MySQL's convention for booleans
+		if params == "1" || column.Length == 1 {
+			return "bool"
+		}
+		return "int64"
+	case "smallint", "mediumint", "int", "integer", "bigint", "int2", "int4", "int8", "serial", "smallserial", "bigserial", "unsigned", "year":
+		return "int64"
+	case "float", "float4", "float8", "double", "real", "decimal", "dec", "numeric", "money", "smallmoney":
+		return "float64"
+	case "date", "datetime", "datetime2", "smalldatetime", "datetimeoffset", "timestamp", "timestamptz", "time", "timetz":
+		return "time.Time"
+	case "blob", "tinyblob", "mediumblob", "longblob", "binary", "varbinary", "bytea", "image":
+		return "[]byte"
+	}
+	return "string"
+}
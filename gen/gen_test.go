@@ -0,0 +1,209 @@
+package gen_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nkovacs/gorm"
+	_ "github.com/nkovacs/gorm/dialects/sqlite"
+	"github.com/nkovacs/gorm/gen"
+)
+
+type Author struct {
+	ID    int64
+	Name  string `sql:"size:100;not null;index:idx_authors_name"`
+	Email string `sql:"size:255;unique"`
+}
+
+type Book struct {
+	ID          int64
+	Title       string `sql:"size:200;unique_index:uix_books_title_author,priority:1"`
+	AuthorID    int64  `sql:"unique_index:uix_books_title_author,priority:2"`
+	Author      Author `gorm:"constraint:OnDelete:CASCADE"`
+	Price       float64
+	PublishedAt *time.Time
+}
+
+type Biography struct {
+	ID       int64
+	AuthorID int64  `sql:"unique"`
+	Author   Author `gorm:"constraint:OnDelete:CASCADE"`
+	Content  string
+}
+
+func openDB(t *testing.T) *gorm.DB {
+	dir, err := ioutil.TempDir("", "gorm-gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	db, err := gorm.Open("sqlite3", filepath.Join(dir, "legacy.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(&Author{}, &Book{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	db.Exec("CREATE TABLE ignored_logs (message text)")
+	return db
+}
+
+func TestGenerate(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	source, err := gen.New(db, "models").Generate("authors", "books")
+	if err != nil {
+		t.Fatalf("No error should happen when generate models, but got %+v", err)
+	}
+
+	code := string(source)
+	if _, err := parser.ParseFile(token.NewFileSet(), "models.go", source, 0); err != nil {
+		t.Fatalf("Generated code should be valid go, but got %+v\n%v", err, code)
+	}
+
+	for _, expected := range []string{
+		"package models",
+		`import "time"`,
+		"type Author struct {",
+		"ID    int64   `gorm:\"column:id;primary_key\"`",
+		"Name  string  `gorm:\"column:name;size:100;not null;index:idx_authors_name\"`",
+		"Email *string `gorm:\"column:email;size:255;unique\"`",
+		"Books []Book  `gorm:\"foreignkey:AuthorID\"`",
+		"func (Author) TableName() string {\n\treturn \"authors\"\n}",
+		"type Book struct {",
+		"Title       *string    `gorm:\"column:title;size:200;unique_index:uix_books_title_author,priority:1\"`",
+		"AuthorID    *int64     `gorm:\"column:author_id;type:bigint;unique_index:uix_books_title_author,priority:2\"`",
+		"Price       *float64   `gorm:\"column:price;type:REAL\"`",
+		"PublishedAt *time.Time `gorm:\"column:published_at;type:datetime\"`",
+		"Author      *Author    `gorm:\"foreignkey:AuthorID;constraint:OnDelete:CASCADE\"`",
+		"func (Book) TableName() string {\n\treturn \"books\"\n}",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Generated code should contain %v, but got\n%v", expected, code)
+		}
+	}
+
+	if strings.Contains(code, "IgnoredLog") {
+		t.Errorf("Generated code should only contain given tables, but got\n%v", code)
+	}
+}
+
+func TestGenerateHasOne(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := db.AutoMigrate(&Biography{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := gen.New(db, "models").Generate("authors", "biographies")
+	if err != nil {
+		t.Fatalf("No error should happen when generate models, but got %+v", err)
+	}
+
+	code := string(source)
+	if !strings.Contains(code, "Biography *Biography `gorm:\"foreignkey:AuthorID\"`") || strings.Contains(code, "[]Biography") {
+		t.Errorf("Unique foreign key should generate has one relation, but got\n%v", code)
+	}
+}
+
+func TestGenerateColumnTypes(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	db.Exec(`CREATE TABLE measures (id integer primary key, enabled tinyint(1) NOT NULL, level tinyint NOT NULL, location point NOT NULL,
+		duration interval NOT NULL, ratio double precision NOT NULL, amount bigint unsigned, note varchar(20), created_at timestamp with time zone)`)
+
+	source, err := gen.New(db, "models").Generate("measures")
+	if err != nil {
+		t.Fatalf("No error should happen when generate models, but got %+v", err)
+	}
+
+	code := string(source)
+	for _, expected := range []string{
+		"Enabled   bool       `",
+		"Level     int64      `",
+		"Location  string     `",
+		"Duration  string     `",
+		"Ratio     float64    `",
+		"Amount    *int64     `",
+		"Note      *string    `",
+		"CreatedAt *time.Time `",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Generated code should contain %v, but got\n%v", expected, code)
+		}
+	}
+}
+
+func TestGenerateEscapedColumns(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	db.Exec("CREATE TABLE reviews (id integer primary key, author_id integer NOT NULL, `author id` integer NOT NULL REFERENCES authors(id), " +
+		"label varchar(50) NOT NULL DEFAULT 'say \"hi\" `now`')")
+
+	source, err := gen.New(db, "models").Generate("authors", "reviews")
+	if err != nil {
+		t.Fatalf("No error should happen when generate models, but got %+v", err)
+	}
+
+	code := string(source)
+	file, err := parser.ParseFile(token.NewFileSet(), "models.go", source, 0)
+	if err != nil {
+		t.Fatalf("Generated code should be valid go, but got %+v\n%v", err, code)
+	}
+
+	tags := map[string]string{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if field, ok := node.(*ast.Field); ok && field.Tag != nil && len(field.Names) == 1 {
+			tag, _ := strconv.Unquote(field.Tag.Value)
+			tags[field.Names[0].Name] = reflect.StructTag(tag).Get("gorm")
+		}
+		return true
+	})
+
+	if tags["ColumnAuthorID"] != "column:author id;type:INTEGER;not null" || tags["Reviews"] != "foreignkey:ColumnAuthorID" || tags["Author"] != "foreignkey:ColumnAuthorID" {
+		t.Errorf("Foreign keys should use generated field names, but got %v\n%v", tags, code)
+	}
+
+	if tags["Label"] != "column:label;size:50;not null;default:'say \"hi\" `now`'" {
+		t.Errorf("Quotes and backticks of default value should be escaped, but got %v\n%v", tags["Label"], code)
+	}
+}
+
+func TestModels(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	models, err := gen.New(db, "models").Models()
+	if err != nil {
+		t.Fatalf("No error should happen when build models, but got %+v", err)
+	}
+
+	var names []string
+	for _, model := range models {
+		names = append(names, model.Name+":"+model.TableName)
+	}
+
+	if strings.Join(names, ",") != "Author:authors,Book:books,IgnoredLog:ignored_logs" {
+		t.Errorf("Models should be built for all tables, but got %v", names)
+	}
+
+	if _, err := gen.New(db, "models").Models("missing_table"); err == nil {
+		t.Errorf("Should got error when build model for missing table")
+	}
+}
@@ -0,0 +1,102 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// GenerateQuery generate typed fields of models with package `github.com/nkovacs/gorm/gen/field`, for example:
+//     source, err := gen.New(db, "query").GenerateQuery(&User{}, &Email{})
+// then conditions built with generated code are checked when compiling:
+//     q := query.New()
+//     db.Where(q.User.Name.Eq("jinzhu")).Order(q.User.Age.Desc()).Pluck(q.User.Name.Column(), &names)
+// a model given more than once is generated once, models of different packages with the same name return error
+func (g *Generator) GenerateQuery(models ...interface{}) ([]byte, error) {
+	var (
+		types, values bytes.Buffer
+		names         []string
+		modelTypes    = map[string]string{}
+	)
+
+	for _, model := range models {
+		scope := g.DB.NewScope(model)
+		modelStruct := scope.GetModelStruct()
+		if modelStruct.ModelType == nil || modelStruct.ModelType.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%T is not a struct", model)
+		}
+
+		var (
+			name      = modelStruct.ModelType.Name()
+			modelType = modelStruct.ModelType.PkgPath() + "." + name
+			tableName = scope.TableName()
+		)
+
+		if generated, ok := modelTypes[name]; ok {
+			if generated == modelType {
+				continue
+			}
+			return nil, fmt.Errorf("%v and %v would both generate query struct %v", generated, modelType, name)
+		}
+		modelTypes[name] = modelType
+		names = append(names, name)
+
+		fmt.Fprintf(&types, "\n// %v typed fields of table `%v`\ntype %v struct {\n", name, tableName, name)
+		fmt.Fprintf(&values, "%v: %v{\n", name, name)
+		for _, field := range modelStruct.StructFields {
+			if field.IsIgnored || !field.IsNormal {
+				continue
+			}
+
+			fieldType, typeArgs := fieldTypeOf(field.Struct.Type)
+			fmt.Fprintf(&types, "%v field.%v%v\n", field.Name, fieldType, typeArgs)
+			fmt.Fprintf(&values, "%v: field.New%v%v(%q, %q),\n", field.Name, fieldType, typeArgs, tableName, field.DBName)
+		}
+		fmt.Fprintf(&types, "}\n")
+		fmt.Fprintf(&values, "},\n")
+	}
+
+	var source bytes.Buffer
+	fmt.Fprintf(&source, "// Code generated by gorm-gen. DO NOT EDIT.\n\npackage %v\n\n", g.Package)
+	fmt.Fprintf(&source, "import \"github.com/nkovacs/gorm/gen/field\"\n\n")
+	fmt.Fprintf(&source, "// Query typed fields of models\ntype Query struct {\n")
+	for _, name := range names {
+		fmt.Fprintf(&source, "%v %v\n", name, name)
+	}
+	fmt.Fprintf(&source, "}\n")
+	source.Write(types.Bytes())
+	fmt.Fprintf(&source, "\n// New initialize typed fields of models\nfunc New() *Query {\nreturn &Query{\n")
+	source.Write(values.Bytes())
+	fmt.Fprintf(&source, "}\n}\n")
+
+	return format.Source(source.Bytes())
+}
+
+// fieldTypeOf type in package field for struct field and its type arguments, e.g. `Number` and `[int64]`,
+// named types like `sql.NullString` use untyped `Field`
+func fieldTypeOf(fieldType reflect.Type) (string, string) {
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if fieldType == reflect.TypeOf(time.Time{}) {
+		return "Time", ""
+	}
+
+	if fieldType.PkgPath() != "" {
+		return "Field", ""
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Bool:
+		return strings.Title(fieldType.Kind().String()), ""
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "Number", "[" + fieldType.Kind().String() + "]"
+	}
+	return "Field", ""
+}
@@ -0,0 +1,82 @@
+package gen_test
+
+import (
+	"database/sql"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/nkovacs/gorm"
+	"github.com/nkovacs/gorm/gen"
+)
+
+type Reader struct {
+	ID       uint
+	Name     string
+	Age      int32
+	Score    *float64
+	Nickname sql.NullString
+	Books    []Book
+	Ignored  string `sql:"-"`
+}
+
+func TestGenerateQuery(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	source, err := gen.New(db, "query").GenerateQuery(&Reader{}, &Book{})
+	if err != nil {
+		t.Fatalf("No error should happen when generate query, but got %+v", err)
+	}
+
+	code := string(source)
+	if _, err := parser.ParseFile(token.NewFileSet(), "query.go", source, 0); err != nil {
+		t.Fatalf("Generated code should be valid go, but got %+v\n%v", err, code)
+	}
+
+	for _, expected := range []string{
+		"package query",
+		`import "github.com/nkovacs/gorm/gen/field"`,
+		"Reader Reader\n\tBook   Book\n}",
+		"ID       field.Number[uint]\n\tName     field.String\n\tAge      field.Number[int32]\n\tScore    field.Number[float64]\n\tNickname field.Field\n}",
+		`ID:       field.NewNumber[uint]("readers", "id"),`,
+		`Nickname: field.NewField("readers", "nickname"),`,
+		`PublishedAt: field.NewTime("books", "published_at"),`,
+		"func New() *Query {",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Generated code should contain %v, but got\n%v", expected, code)
+		}
+	}
+
+	if strings.Contains(code, "Books") || strings.Contains(code, "Ignored") {
+		t.Errorf("Generated code should not contain relations or ignored fields, but got\n%v", code)
+	}
+
+	if _, err := gen.New(db, "query").GenerateQuery(&[]string{}); err == nil {
+		t.Errorf("Should got error when generate query for non struct")
+	}
+}
+
+func TestGenerateQueryWithSameNames(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	source, err := gen.New(db, "query").GenerateQuery(&Reader{}, Reader{})
+	if err != nil {
+		t.Fatalf("No error should happen when generate query for same model twice, but got %+v", err)
+	}
+
+	if code := string(source); strings.Count(code, "type Reader struct") != 1 || strings.Count(code, "Reader: Reader{") != 1 {
+		t.Errorf("Same model should be generated once, but got\n%v", code)
+	}
+
+	type Model struct {
+		ID uint
+	}
+
+	if _, err := gen.New(db, "query").GenerateQuery(&Model{}, &gorm.Model{}); err == nil {
+		t.Errorf("Should got error when models of different packages have same name")
+	}
+}
@@ -0,0 +1,159 @@
+//go:build go1.18
+// +build go1.18
+
+package gorm
+
+import "context"
+
+// Generic typed wrapper of DB, queries are still built with `Scope` and run through callbacks,
+// they are run with the context set with `DB.WithContext`, so they are cancelled when it is done, for example:
+//     users, err := gorm.G[User](db).Where("name = ?", "jinzhu").Order("age DESC").Find(ctx)
+//     user, err := gorm.G[User](db).Where(q.User.Name.Eq("jinzhu")).First(ctx)
+type Generic[T any] struct {
+	db *DB
+}
+
+// G initialize typed wrapper for model type T, T should be a struct type
+func G[T any](db *DB) Generic[T] {
+	return Generic[T]{db: db}
+}
+
+// DB underlying DB with conditions applied
+func (g Generic[T]) DB() *DB {
+	return g.db
+}
+
+// Where refer `DB.Where`
+func (g Generic[T]) Where(query interface{}, args ...interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Where(query, args...)}
+}
+
+// Or refer `DB.Or`
+func (g Generic[T]) Or(query interface{}, args ...interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Or(query, args...)}
+}
+
+// Not refer `DB.Not`
+func (g Generic[T]) Not(query interface{}, args ...interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Not(query, args...)}
+}
+
+// Select refer `DB.Select`
+func (g Generic[T]) Select(query interface{}, args ...interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Select(query, args...)}
+}
+
+// Order refer `DB.Order`
+func (g Generic[T]) Order(value interface{}, reorder ...bool) Generic[T] {
+	return Generic[T]{db: g.db.Order(value, reorder...)}
+}
+
+// Limit refer `DB.Limit`
+func (g Generic[T]) Limit(limit interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Limit(limit)}
+}
+
+// Offset refer `DB.Offset`
+func (g Generic[T]) Offset(offset interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Offset(offset)}
+}
+
+// Joins refer `DB.Joins`
+func (g Generic[T]) Joins(query string, args ...interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Joins(query, args...)}
+}
+
+// Preload refer `DB.Preload`
+func (g Generic[T]) Preload(column string, conditions ...interface{}) Generic[T] {
+	return Generic[T]{db: g.db.Preload(column, conditions...)}
+}
+
+// Scopes refer `DB.Scopes`
+func (g Generic[T]) Scopes(funcs ...func(*DB) *DB) Generic[T] {
+	return Generic[T]{db: g.db.Scopes(funcs...)}
+}
+
+// Unscoped refer `DB.Unscoped`
+func (g Generic[T]) Unscoped() Generic[T] {
+	return Generic[T]{db: g.db.Unscoped()}
+}
+
+// Find find records that match current conditions
+func (g Generic[T]) Find(ctx context.Context) ([]T, error) {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []T
+	return results, db.Find(&results).Error
+}
+
+// First find first record that match current conditions, order by primary key, return `ErrRecordNotFound` if not found
+func (g Generic[T]) First(ctx context.Context) (result T, err error) {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return result, err
+	}
+	return result, db.First(&result).Error
+}
+
+// Last find last record that match current conditions, order by primary key, return `ErrRecordNotFound` if not found
+func (g Generic[T]) Last(ctx context.Context) (result T, err error) {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return result, err
+	}
+	return result, db.Last(&result).Error
+}
+
+// Iterate query records one by one and pass them to fc, refer `DB.Iterate`
+func (g Generic[T]) Iterate(ctx context.Context, fc func(*T) error) error {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Iterate(fc).Error
+}
+
+// Count count records that match current conditions
+func (g Generic[T]) Count(ctx context.Context) (count int64, err error) {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return count, db.Model(new(T)).Count(&count).Error
+}
+
+// Create insert value into database
+func (g Generic[T]) Create(ctx context.Context, value *T) error {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return err
+	}
+	return db.Create(value).Error
+}
+
+// Delete delete records that match current conditions, return deleted rows count,
+// `ErrMissingWhereClause` is returned without conditions, delete all records with `Where("1 = 1")` explicitly
+func (g Generic[T]) Delete(ctx context.Context) (int64, error) {
+	db, err := g.withContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if search := db.search; len(search.whereConditions) == 0 && len(search.orConditions) == 0 && len(search.notConditions) == 0 {
+		return 0, ErrMissingWhereClause
+	}
+
+	db = db.Delete(new(T))
+	return db.RowsAffected, db.Error
+}
+
+// withContext return error if context is done already, otherwise DB whose queries are run with the context
+func (g Generic[T]) withContext(ctx context.Context) (*DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return g.db.WithContext(ctx), nil
+}
@@ -0,0 +1,90 @@
+//go:build go1.18
+// +build go1.18
+
+package gorm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nkovacs/gorm"
+)
+
+type genericTraceKey struct{}
+
+type GenericUser struct {
+	ID    int64
+	Name  string
+	Age   int64
+	Trace string `sql:"-"`
+}
+
+func (user *GenericUser) AfterFind(scope *gorm.Scope) {
+	if ctx, ok := scope.Get("gorm:context"); ok {
+		user.Trace, _ = ctx.(context.Context).Value(genericTraceKey{}).(string)
+	}
+}
+
+func TestGenericAPI(t *testing.T) {
+	DB.DropTableIfExists(&GenericUser{})
+	DB.AutoMigrate(&GenericUser{})
+
+	ctx := context.WithValue(context.Background(), genericTraceKey{}, "trace-1")
+	for _, user := range []GenericUser{{Name: "generic1", Age: 10}, {Name: "generic2", Age: 20}, {Name: "generic3", Age: 30}} {
+		if err := gorm.G[GenericUser](DB).Create(ctx, &user); err != nil || user.ID == 0 {
+			t.Errorf("No error should happen when create with generic API, but got %+v", err)
+		}
+	}
+
+	users, err := gorm.G[GenericUser](DB).Where("age > ?", 10).Order("age DESC").Find(ctx)
+	if err != nil || len(users) != 2 || users[0].Name != "generic3" || users[1].Name != "generic2" {
+		t.Errorf("Should find users with generic API, but got %+v, %+v", users, err)
+	}
+
+	if len(users) > 0 && users[0].Trace != "trace-1" {
+		t.Errorf("Callbacks should be able to read context, but got %+v", users[0])
+	}
+
+	user, err := gorm.G[GenericUser](DB).Where(gorm.Expr("name = ?", "generic2")).First(ctx)
+	if err != nil || user.Age != 20 {
+		t.Errorf("Should find first user with generic API, but got %+v, %+v", user, err)
+	}
+
+	if user, err := gorm.G[GenericUser](DB).Last(ctx); err != nil || user.Name != "generic3" {
+		t.Errorf("Should find last user with generic API, but got %+v, %+v", user, err)
+	}
+
+	if _, err := gorm.G[GenericUser](DB).Where("name = ?", "none").First(ctx); err != gorm.ErrRecordNotFound {
+		t.Errorf("Should got ErrRecordNotFound when no record found, but got %+v", err)
+	}
+
+	if count, err := gorm.G[GenericUser](DB).Where("age >= ?", 20).Count(ctx); err != nil || count != 2 {
+		t.Errorf("Should count users with generic API, but got %v, %+v", count, err)
+	}
+
+	var ages []int64
+	if err := gorm.G[GenericUser](DB).Order("age").Iterate(ctx, func(user *GenericUser) error {
+		ages = append(ages, user.Age)
+		return nil
+	}); err != nil || len(ages) != 3 || ages[2] != 30 {
+		t.Errorf("Should iterate users with generic API, but got %v, %+v", ages, err)
+	}
+
+	if deleted, err := gorm.G[GenericUser](DB).Where("name = ?", "generic1").Delete(ctx); err != nil || deleted != 1 {
+		t.Errorf("Should delete user with generic API, but got %v, %+v", deleted, err)
+	}
+
+	if deleted, err := gorm.G[GenericUser](DB).Delete(ctx); err != gorm.ErrMissingWhereClause || deleted != 0 {
+		t.Errorf("Should got ErrMissingWhereClause when delete without conditions, but got %v, %+v", deleted, err)
+	}
+
+	if count, err := gorm.G[GenericUser](DB).Count(ctx); err != nil || count != 2 {
+		t.Errorf("Records shouldn't be deleted without conditions, but got %v, %+v", count, err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := gorm.G[GenericUser](DB).Find(cancelled); err != context.Canceled {
+		t.Errorf("Should got context error when context is done, but got %+v", err)
+	}
+}
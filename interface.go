@@ -1,6 +1,9 @@
 package gorm
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+)
 
 type sqlCommon interface {
 	Exec(query string, args ...interface{}) (sql.Result, error)
@@ -9,11 +12,42 @@ type sqlCommon interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
+// sqlContextCommon sqlCommon that could run queries with context, e.g. *sql.DB and *sql.Tx
+type sqlContextCommon interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 type sqlDb interface {
 	Begin() (*sql.Tx, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
 
 type sqlTx interface {
 	Commit() error
 	Rollback() error
 }
+
+// contextDB run queries with context set with `DB.WithContext`, so they are cancelled when the context is done
+type contextDB struct {
+	db  sqlContextCommon
+	ctx context.Context
+}
+
+func (db contextDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.db.ExecContext(db.ctx, query, args...)
+}
+
+func (db contextDB) Prepare(query string) (*sql.Stmt, error) {
+	return db.db.PrepareContext(db.ctx, query)
+}
+
+func (db contextDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.db.QueryContext(db.ctx, query, args...)
+}
+
+func (db contextDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.db.QueryRowContext(db.ctx, query, args...)
+}
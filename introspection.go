@@ -0,0 +1,140 @@
+package gorm
+
+import (
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IntrospectionDialect dialect that could read schema of database, e.g. to generate models
+//     if introspector, ok := db.Dialect().(gorm.IntrospectionDialect); ok {
+//         tableNames, err := introspector.Tables()
+//     }
+type IntrospectionDialect interface {
+	// Tables return names of all tables in current database
+	Tables() ([]string, error)
+	// ColumnTypes return column definitions of table
+	ColumnTypes(tableName string) ([]ColumnType, error)
+	// Indexes return indexes of table, including primary key
+	Indexes(tableName string) ([]TableIndex, error)
+	// ForeignKeys return foreign keys of table
+	ForeignKeys(tableName string) ([]TableForeignKey, error)
+}
+
+// ColumnType column definition read from database with `IntrospectionDialect.ColumnTypes`
+type ColumnType struct {
+	Name          string
+	DatabaseType  string
+	Length        int64
+	Precision     int64
+	Scale         int64
+	Nullable      bool
+	Default       sql.NullString
+	PrimaryKey    bool
+	Unique        bool
+	AutoIncrement bool
+	Comment       string
+}
+
+// TableIndex index definition read from database with `IntrospectionDialect.Indexes`
+type TableIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+}
+
+// TableForeignKey foreign key definition read from database with `IntrospectionDialect.ForeignKeys`
+type TableForeignKey struct {
+	Name              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	OnDelete          string
+	OnUpdate          string
+}
+
+var columnTypeRegexp = regexp.MustCompile(`^\s*([^(]*?)\s*(?:\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\))?\s*$`)
+
+// parseColumnType parse declared column type like `varchar(255)` or `decimal(10,2)` into column type
+func parseColumnType(columnType *ColumnType, declaredType string) {
+	matches := columnTypeRegexp.FindStringSubmatch(declaredType)
+	if matches == nil {
+		columnType.DatabaseType = declaredType
+		return
+	}
+
+	columnType.DatabaseType = matches[1]
+	if matches[3] != "" {
+		columnType.Precision, _ = strconv.ParseInt(matches[2], 10, 64)
+		columnType.Scale, _ = strconv.ParseInt(matches[3], 10, 64)
+	} else if matches[2] != "" {
+		columnType.Length, _ = strconv.ParseInt(matches[2], 10, 64)
+	}
+}
+
+// AppendIndexColumn append column to index with given name, indexes are created in the order they are found
+func AppendIndexColumn(indexes []TableIndex, name string, column string, unique bool, primary bool) []TableIndex {
+	if len(indexes) > 0 && indexes[len(indexes)-1].Name == name {
+		indexes[len(indexes)-1].Columns = append(indexes[len(indexes)-1].Columns, column)
+		return indexes
+	}
+	return append(indexes, TableIndex{Name: name, Columns: []string{column}, Unique: unique, Primary: primary})
+}
+
+// AppendForeignKeyColumn append column to foreign key with given name, foreign keys are created in the order they are found, actions are upper cased
+func AppendForeignKeyColumn(foreignKeys []TableForeignKey, name string, column string, referencedTable string, referencedColumn string, onDelete string, onUpdate string) []TableForeignKey {
+	if len(foreignKeys) > 0 && foreignKeys[len(foreignKeys)-1].Name == name {
+		foreignKey := &foreignKeys[len(foreignKeys)-1]
+		foreignKey.Columns = append(foreignKey.Columns, column)
+		foreignKey.ReferencedColumns = append(foreignKey.ReferencedColumns, referencedColumn)
+		return foreignKeys
+	}
+
+	return append(foreignKeys, TableForeignKey{
+		Name:              name,
+		Columns:           []string{column},
+		ReferencedTable:   referencedTable,
+		ReferencedColumns: []string{referencedColumn},
+		OnDelete:          strings.ToUpper(onDelete),
+		OnUpdate:          strings.ToUpper(onUpdate),
+	})
+}
+
+// MarkUniqueColumns set columns' primary key flag with primary indexes, and unique flag with single column unique indexes
+func MarkUniqueColumns(columnTypes []ColumnType, indexes []TableIndex) {
+	for _, index := range indexes {
+		for i := range columnTypes {
+			for _, column := range index.Columns {
+				if columnTypes[i].Name != column {
+					continue
+				}
+
+				if index.Primary {
+					columnTypes[i].PrimaryKey = true
+				} else if index.Unique && len(index.Columns) == 1 {
+					columnTypes[i].Unique = true
+				}
+			}
+		}
+	}
+}
+
+// ScanStrings scan rows with single string column, rows are closed after scanning
+//     tableNames, err := gorm.ScanStrings(db.Query("SELECT name FROM tables"))
+func ScanStrings(rows *sql.Rows, err error) (results []string, _ error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
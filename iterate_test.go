@@ -0,0 +1,106 @@
+package gorm_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestIterate(t *testing.T) {
+	for _, name := range []string{"iterate1", "iterate2", "iterate3", "iterate4", "iterate5"} {
+		DB.Save(getPreparedUser(name, "iterate"))
+	}
+
+	var names []string
+	err := DB.Model(&User{}).Where("name LIKE ?", "iterate%").Order("name").Preload("Emails").Preload("Languages").
+		Set("gorm:iterate_batch_size", 2).Iterate(func(user *User) error {
+		if len(user.Emails) != 2 || len(user.Languages) != 2 {
+			t.Errorf("Associations should be preloaded for %v, but got %+v, %+v", user.Name, user.Emails, user.Languages)
+		}
+		names = append(names, user.Name)
+		return nil
+	}).Error
+
+	if err != nil || len(names) != 5 || names[0] != "iterate1" || names[4] != "iterate5" {
+		t.Errorf("Should iterate all users, but got %v, %+v", names, err)
+	}
+
+	// model is inferred from the function, stop when function returns error
+	var (
+		count   int
+		errStop = errors.New("stop iterating")
+	)
+
+	db := DB.Where("name LIKE ?", "iterate%").Iterate(func(user *User) error {
+		if count++; count == 2 {
+			return errStop
+		}
+		return nil
+	})
+
+	if db.Error != errStop || count != 2 {
+		t.Errorf("Should stop iterating when got error, but got %v, %+v", count, db.Error)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	count = 0
+	err = DB.WithContext(ctx).Where("name LIKE ?", "iterate%").Iterate(func(user *User) error {
+		count++
+		cancel()
+		return nil
+	}).Error
+
+	if err != context.Canceled || count != 1 {
+		t.Errorf("Should stop iterating when context is cancelled, but got %v, %+v", count, err)
+	}
+
+	if err := DB.Model(&User{}).Iterate(func(user User) {}).Error; err == nil {
+		t.Errorf("Should got error when iterate with invalid function")
+	}
+}
+
+func TestIterateInTransaction(t *testing.T) {
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	for _, name := range []string{"iterate_tx1", "iterate_tx2", "iterate_tx3"} {
+		tx.Save(getPreparedUser(name, "iterate_tx"))
+	}
+
+	var names []string
+	err := tx.Model(&User{}).Where("name LIKE ?", "iterate_tx%").Order("name").Preload("Emails").
+		Set("gorm:iterate_batch_size", 2).Iterate(func(user *User) error {
+		if len(user.Emails) != 2 {
+			t.Errorf("Associations should be preloaded in transaction for %v, but got %+v", user.Name, user.Emails)
+		}
+
+		// queries could be run with the transaction while iterating
+		var count int
+		if err := tx.Model(&Email{}).Where("user_id = ?", user.Id).Count(&count).Error; err != nil || count != 2 {
+			t.Errorf("Should count emails in transaction while iterating, but got %v, %+v", count, err)
+		}
+		names = append(names, user.Name)
+		return nil
+	}).Error
+
+	if err != nil || len(names) != 3 || names[0] != "iterate_tx1" || names[2] != "iterate_tx3" {
+		t.Errorf("Should iterate all users in transaction, but got %v, %+v", names, err)
+	}
+
+	// every batch is a query with keyset condition of previous batch's last record
+	names = nil
+	err = tx.Model(&User{}).Where("name LIKE ?", "iterate_tx%").Order("name DESC").Offset(1).Limit(2).
+		Set("gorm:iterate_batch_size", 1).Iterate(func(user *User) error {
+		names = append(names, user.Name)
+		return nil
+	}).Error
+
+	if err != nil || strings.Join(names, ",") != "iterate_tx2,iterate_tx1" {
+		t.Errorf("Should iterate users in batches with orders, offset and limit in transaction, but got %v, %+v", names, err)
+	}
+
+	if err := tx.Model(&User{}).Order("lower(name)").Iterate(func(user *User) error { return nil }).Error; err == nil {
+		t.Errorf("Should got error when orders couldn't be used to iterate in batches in transaction")
+	}
+}
@@ -1,11 +1,13 @@
 package gorm
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,6 +27,12 @@ type DB struct {
 	source            string
 	values            map[string]interface{}
 	joinTableHandlers map[string]JoinTableHandler
+
+	nowFunc                func() time.Time
+	skipDefaultTransaction bool
+	namingStrategy         NamingStrategy
+	modelStructs           *safeModelStructsMap
+	namingMutex            sync.RWMutex
 }
 
 // Open initialize a new db connection, need to import driver first, e.g:
@@ -38,56 +46,62 @@ type DB struct {
 //    // import _ "github.com/jinzhu/gorm/dialects/postgres"
 //    // import _ "github.com/jinzhu/gorm/dialects/sqlite"
 //    // import _ "github.com/jinzhu/gorm/dialects/mssql"
+// configuration of dialect could be passed with `DialectConfig` after other arguments, use `OpenWith` for other configurations
+//    db, err := gorm.Open("mysql", "user:password@/dbname", gorm.DialectConfig{ServerVersion: "8.0.21"})
+// an existing `*sql.DB` could be used instead of data source name
+//    db, err := gorm.Open("mysql", sqlDB)
 func Open(dialect string, args ...interface{}) (*DB, error) {
-	var db DB
-	var err error
+	var config Config
 
-	if len(args) == 0 {
-		err = errors.New("invalid database source")
-	} else {
-		var source string
-		var dbSQL sqlCommon
-
-		switch value := args[0].(type) {
-		case string:
-			var driver = dialect
-			if len(args) == 1 {
-				source = value
-			} else if len(args) >= 2 {
-				driver = value
-				source = args[1].(string)
-			}
-			dbSQL, err = sql.Open(driver, source)
-		case sqlCommon:
-			source = reflect.Indirect(reflect.ValueOf(value)).FieldByName("dsn").String()
-			dbSQL = value
+	for idx := 0; idx < len(args); idx++ {
+		if dialectConfig, ok := args[idx].(DialectConfig); ok {
+			config.Dialect = dialectConfig
+			args = append(args[:idx:idx], args[idx+1:]...)
+			idx--
 		}
+	}
 
-		db = DB{
-			dialect:   newDialect(dialect, dbSQL.(*sql.DB)),
-			logger:    defaultLogger,
-			callbacks: DefaultCallback,
-			source:    source,
-			values:    map[string]interface{}{},
-			db:        dbSQL,
+	if len(args) == 0 {
+		return nil, errors.New("invalid database source")
+	}
+
+	switch value := args[0].(type) {
+	case string:
+		var driver, source = dialect, value
+		if len(args) >= 2 {
+			driver = value
+			source = args[1].(string)
 		}
-		db.parent = &db
 
-		if err == nil {
-			err = db.DB().Ping() // Send a ping to make sure the database connection is alive.
+		sqlDB, err := sql.Open(driver, source)
+		if err != nil {
+			return nil, err
 		}
-	}
 
-	return &db, err
+		db, err := OpenWith(dialect, sqlDB, config)
+		db.source = source
+		return db, err
+	case *sql.DB:
+		return OpenWith(dialect, value, config)
+	}
+	return nil, fmt.Errorf("invalid database source %T, should be data source name or *sql.DB", args[0])
 }
 
 // Close close current db connection
 func (s *DB) Close() error {
-	return s.parent.db.(*sql.DB).Close()
+	if db, ok := s.parent.db.(interface {
+		Close() error
+	}); ok {
+		return db.Close()
+	}
+	return ErrInvalidSQL
 }
 
 // DB get `*sql.DB` from current connection
 func (s *DB) DB() *sql.DB {
+	if db, ok := s.db.(*preparedStmtDB); ok {
+		return db.DB
+	}
 	return s.db.(*sql.DB)
 }
 
@@ -142,10 +156,41 @@ func (s *DB) LogMode(enable bool) *DB {
 // SingularTable use singular table by default
 func (s *DB) SingularTable(enable bool) {
 	modelStructsMap = newModelStructsMap()
+	s.parent.namingMutex.Lock()
 	s.parent.singularTable = enable
+	s.parent.namingMutex.Unlock()
+}
+
+// SetNamingStrategy set naming strategy of tables, columns, indexes and constraints, models used by the DB are parsed again with it,
+// other DBs are not affected
+//     db.SetNamingStrategy(gorm.DefaultNamingStrategy{TablePrefix: "t_", SingularTable: true})
+func (s *DB) SetNamingStrategy(namingStrategy NamingStrategy) {
+	s.parent.namingMutex.Lock()
+	defer s.parent.namingMutex.Unlock()
+	s.parent.namingStrategy = namingStrategy
+	s.parent.modelStructs = newModelStructsMap()
+}
+
+// NamingStrategy return naming strategy of the DB, which is `DefaultNamingStrategy` if it isn't set
+func (s *DB) NamingStrategy() NamingStrategy {
+	s.parent.namingMutex.RLock()
+	defer s.parent.namingMutex.RUnlock()
+	if s.parent.namingStrategy != nil {
+		return s.parent.namingStrategy
+	}
+	return DefaultNamingStrategy{SingularTable: s.parent.singularTable}
+}
+
+// customNaming return naming strategy set for the DB and model structs parsed with it, they are nil if it isn't set
+func (s *DB) customNaming() (NamingStrategy, *safeModelStructsMap) {
+	s.parent.namingMutex.RLock()
+	defer s.parent.namingMutex.RUnlock()
+	return s.parent.namingStrategy, s.parent.modelStructs
 }
 
-// Where return a new relation, filter records with given conditions, accepts `map`, `struct` or `string` as conditions, refer http://jinzhu.github.io/gorm/curd.html#query
+// Where return a new relation, filter records with given conditions, accepts `map`, `struct`, `string` or `Expr` as conditions, refer http://jinzhu.github.io/gorm/curd.html#query
+// fields of relations like `Company.Name` could be used in string conditions, the relation will be joined automatically
+//     db.Where("Company.Name = ?", "jinzhu").Find(&users)
 func (s *DB) Where(query interface{}, args ...interface{}) *DB {
 	return s.clone().search.Where(query, args...).db
 }
@@ -201,6 +246,10 @@ func (s *DB) Having(query string, values ...interface{}) *DB {
 
 // Joins specify Joins conditions
 //     db.Joins("JOIN emails ON emails.user_id = users.id AND emails.email = ?", "jinzhu@example.org").Find(&user)
+// relations could be joined with field name, related table is aliased with the name, has one and belongs to relations are loaded
+// in the same query, columns are aliased like `credit_card__number`
+//     db.Joins("CreditCard").Joins("Company").Where("users.name = ?", "jinzhu").Find(&users)
+//     db.Joins("Emails").Where("Emails.Email = ?", "jinzhu@example.org").Find(&users)
 func (s *DB) Joins(query string, args ...interface{}) *DB {
 	return s.clone().search.Joins(query, args...).db
 }
@@ -261,6 +310,87 @@ func (s *DB) Find(out interface{}, where ...interface{}) *DB {
 	return s.clone().NewScope(out).inlineCondition(where...).callCallbacks(s.parent.callbacks.queries).db
 }
 
+// FindInBatches find records in batches of batchSize and pass every batch to fc, records are paged by primary keys
+// with `WHERE pk > last ORDER BY pk LIMIT size`, so fc could update records safely, it stops when fc returns error
+//     db.Where("processed = ?", false).FindInBatches(&users, 100, func(tx *gorm.DB, batch int) error {
+//         for _, user := range users {
+//             tx.Model(&user).Update("processed", true)
+//         }
+//         return nil
+//     })
+func (s *DB) FindInBatches(out interface{}, batchSize int, fc func(tx *DB, batch int) error) *DB {
+	var (
+		c             = s.clone()
+		scope         = c.NewScope(out)
+		results       = reflect.Indirect(reflect.ValueOf(out))
+		primaryFields = scope.GetModelStruct().PrimaryFields
+	)
+
+	if results.Kind() != reflect.Slice {
+		c.AddError(errors.New("FindInBatches requires a pointer to slice"))
+		return c
+	} else if len(primaryFields) == 0 {
+		c.AddError(errors.New("FindInBatches requires primary keys"))
+		return c
+	} else if batchSize <= 0 {
+		c.AddError(errors.New("FindInBatches requires a positive batch size"))
+		return c
+	}
+
+	query := c.Limit(batchSize)
+	for idx, field := range primaryFields {
+		query = query.Order(fmt.Sprintf("%v.%v", scope.QuotedTableName(), scope.Quote(field.DBName)), idx == 0)
+	}
+
+	c.RowsAffected = 0
+	for batch, keyset := 1, query; ; batch++ {
+		if result := keyset.Find(out); result.Error != nil {
+			c.AddError(result.Error)
+			return c
+		}
+
+		count := results.Len()
+		if count == 0 {
+			break
+		}
+		c.RowsAffected += int64(count)
+
+		// take last primary keys before calling fc, in case records are changed
+		var (
+			conditions []string
+			lastValues []interface{}
+			args       []interface{}
+			lastScope  = c.NewScope(reflect.Indirect(results.Index(count - 1)).Addr().Interface())
+		)
+
+		for idx, field := range primaryFields {
+			column := fmt.Sprintf("%v.%v", scope.QuotedTableName(), scope.Quote(field.DBName))
+			value, _ := lastScope.FieldByName(field.Name)
+
+			var condition []string
+			for i := 0; i < idx; i++ {
+				condition = append(condition, fmt.Sprintf("%v.%v = ?", scope.QuotedTableName(), scope.Quote(primaryFields[i].DBName)))
+			}
+			conditions = append(conditions, "("+strings.Join(append(condition, column+" > ?"), " AND ")+")")
+			args = append(append(args, lastValues...), value.Field.Interface())
+			lastValues = append(lastValues, value.Field.Interface())
+		}
+
+		tx := s.New()
+		tx.RowsAffected = int64(count)
+		if err := fc(tx, batch); err != nil {
+			c.AddError(err)
+			return c
+		}
+
+		if count < batchSize {
+			break
+		}
+		keyset = query.Where(strings.Join(conditions, " OR "), args...)
+	}
+	return c
+}
+
 // Scan scan value to a struct
 func (s *DB) Scan(dest interface{}) *DB {
 	return s.clone().NewScope(s.Value).Set("gorm:query_destination", dest).callCallbacks(s.parent.callbacks.queries).db
@@ -285,16 +415,27 @@ func (s *DB) ScanRows(rows *sql.Rows, result interface{}) error {
 	)
 
 	if clone.AddError(err) == nil {
-		scope.scan(rows, columns, scope.Fields())
+		clone.AddError(getScanPlan(scope, columns).newScanner().scan(rows, scope.IndirectValue()))
 	}
 
 	return clone.Error
 }
 
-// Pluck used to query single column from a model as a map
+// Iterate query records one by one and pass them to fc, which should be a `func(*Model) error`, it stops when fc returns error.
+// Preloads are applied to every `gorm:iterate_batch_size` (default 100) records, while the result set is still open,
+// in a transaction records are read in batches of that size by orders and primary keys, as its connection can't run other queries
+// while a result set is open, so orders should be columns of the model.
+//     db.Where("age > ?", 18).Preload("Emails").Iterate(func(user *User) error {
+//         return encoder.Encode(user)
+//     })
+func (s *DB) Iterate(fc interface{}) *DB {
+	return s.NewScope(s.Value).iterate(fc).db
+}
+
+// Pluck used to query single column from a model as a map, column could be a name or an expression created with `Expr`
 //     var ages []int64
 //     db.Find(&users).Pluck("age", &ages)
-func (s *DB) Pluck(column string, value interface{}) *DB {
+func (s *DB) Pluck(column interface{}, value interface{}) *DB {
 	return s.NewScope(s.Value).pluck(column, value).db
 }
 
@@ -415,7 +556,9 @@ func (s *DB) Model(value interface{}) *DB {
 	return c
 }
 
-// Table specify the table you would like to run db operations
+// Table specify the table you would like to run db operations, table name could be qualified with schema, every part is quoted,
+// tables without schema are found in the first existing schema of search_path with postgres
+//     db.Table("reporting.events").Find(&events)
 func (s *DB) Table(name string) *DB {
 	clone := s.clone()
 	clone.search.Table(name)
@@ -432,7 +575,7 @@ func (s *DB) Debug() *DB {
 func (s *DB) Begin() *DB {
 	c := s.clone()
 	if db, ok := c.db.(sqlDb); ok {
-		tx, err := db.Begin()
+		tx, err := beginTx(db, c.getContext())
 		c.db = interface{}(tx).(sqlCommon)
 		c.AddError(err)
 	} else {
@@ -461,6 +604,39 @@ func (s *DB) Rollback() *DB {
 	return s
 }
 
+// Transaction run fc in a transaction, commit it if fc returned nil, otherwise rollback it and return fc's error,
+// with dialects that need client-side retry like cockroach, fc will be run again if the transaction should be retried
+//     db.Transaction(func(tx *gorm.DB) error {
+//         return tx.Create(&user).Error
+//     })
+func (s *DB) Transaction(fc func(tx *DB) error) (err error) {
+	tx := s.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if retrier, ok := s.Dialect().(interface {
+		runTransaction(tx *DB, fc func(tx *DB) error) error
+	}); ok {
+		err = retrier.runTransaction(tx, fc)
+	} else {
+		err = fc(tx)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
 // NewRecord check if value's primary key is blank
 func (s *DB) NewRecord(value interface{}) bool {
 	return s.clone().NewScope(value).PrimaryKeyZero()
@@ -479,8 +655,9 @@ func (s *DB) RecordNotFound() bool {
 // CreateTable create table for models
 func (s *DB) CreateTable(models ...interface{}) *DB {
 	db := s.Unscoped()
+	foreignKeys := db.associationForeignKeys(models...)
 	for _, model := range models {
-		db = db.NewScope(model).createTable().db
+		db = db.NewScope(model).Set("gorm:association_foreign_keys", foreignKeys).createTable().db
 	}
 	return db
 }
@@ -530,8 +707,9 @@ func (s *DB) HasTable(value interface{}) bool {
 // AutoMigrate run auto migration for given models, will only add missing fields, won't delete/change current data
 func (s *DB) AutoMigrate(values ...interface{}) *DB {
 	db := s.Unscoped()
+	foreignKeys := db.associationForeignKeys(values...)
 	for _, value := range values {
-		db = db.NewScope(value).autoMigrate().db
+		db = db.NewScope(value).Set("gorm:association_foreign_keys", foreignKeys).autoMigrate().db
 	}
 	return db
 }
@@ -603,10 +781,55 @@ func (s *DB) Association(column string) *Association {
 
 // Preload preload associations with given conditions
 //    db.Preload("Orders", "state NOT IN (?)", "cancelled").Find(&users)
+// Preload all associations with `*`, nested associations are preloaded up to `gorm:preload_max_depth` (default 3) levels
+//    db.Preload("*").Find(&users)
+//    db.Preload("Orders.*", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).Find(&users)
 func (s *DB) Preload(column string, conditions ...interface{}) *DB {
 	return s.clone().search.Preload(column, conditions...).db
 }
 
+// PreloadCount count has one, has many or many to many associations with one `GROUP BY` query,
+// counts are assigned to field tagged with `count:<relation>`, conditions are same as `Preload`
+//     type User struct {
+//       Orders      []Order
+//       OrdersCount int `gorm:"count:Orders"`
+//     }
+//     db.PreloadCount("Orders", "state = ?", "paid").Find(&users)
+func (s *DB) PreloadCount(column string, conditions ...interface{}) *DB {
+	return s.clone().search.PreloadCount(column, conditions...).db
+}
+
+// WhereHas find records that have associations match conditions with `EXISTS` subquery, conditions are same as `Preload`
+//     db.WhereHas("Orders", "state = ?", "paid").Find(&users)
+//     db.WhereHas("Orders", func(db *gorm.DB) *gorm.DB { return db.Where("amount > ?", 100) }).Find(&users)
+func (s *DB) WhereHas(column string, conditions ...interface{}) *DB {
+	return s.clone().search.Where(&hasCondition{relation: column, conditions: conditions}).db
+}
+
+// WithContext set context for later operations, queries and transactions are run with it, so they are cancelled when it is done,
+// it is saved as setting `gorm:context` so callbacks could read it
+func (s *DB) WithContext(ctx context.Context) *DB {
+	return s.Set("gorm:context", ctx)
+}
+
+// getContext return context set with `WithContext`, or nil if it isn't set
+func (s *DB) getContext() context.Context {
+	if value, ok := s.Get("gorm:context"); ok {
+		if ctx, ok := value.(context.Context); ok {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// beginTx start transaction with context if it isn't nil
+func beginTx(db sqlDb, ctx context.Context) (*sql.Tx, error) {
+	if ctx != nil {
+		return db.BeginTx(ctx, nil)
+	}
+	return db.Begin()
+}
+
 // Set set setting by name, which could be used in callbacks, will clone a new db, and update its setting
 func (s *DB) Set(name string, value interface{}) *DB {
 	return s.clone().InstantSet(name, value)
@@ -630,9 +853,9 @@ func (s *DB) SetJoinTableHandler(source interface{}, column string, handler Join
 	for _, field := range scope.GetModelStruct().StructFields {
 		if field.Name == column || field.DBName == column {
 			if many2many := field.TagSettings["MANY2MANY"]; many2many != "" {
-				source := (&Scope{Value: source}).GetModelStruct().ModelType
-				destination := (&Scope{Value: reflect.New(field.Struct.Type).Interface()}).GetModelStruct().ModelType
-				handler.Setup(field.Relationship, many2many, source, destination)
+				source := s.NewScope(source).GetModelStruct().ModelType
+				destination := s.NewScope(reflect.New(field.Struct.Type).Interface()).GetModelStruct().ModelType
+				handler.Setup(field.Relationship, s.NamingStrategy().JoinTableName(many2many), source, destination)
 				field.Relationship.JoinTableHandler = handler
 				if table := handler.Table(s); scope.Dialect().HasTable(table) {
 					s.Table(table).AutoMigrate(handler)
@@ -695,6 +918,28 @@ func (s *DB) clone() *DB {
 	return &db
 }
 
+// nowTime return current time with `Config.NowFunc` of the DB, or with global `NowFunc`
+func (s *DB) nowTime() time.Time {
+	if s.parent != nil && s.parent.nowFunc != nil {
+		return s.parent.nowFunc()
+	}
+	return NowFunc()
+}
+
+// associationForeignKeys return foreign key constraints defined by has one, has many relations of models,
+// they are created with the associated tables if those are migrated together with the models
+func (s *DB) associationForeignKeys(values ...interface{}) (foreignKeys []*ForeignKey) {
+	for _, value := range values {
+		modelStruct := s.NewScope(value).GetModelStruct()
+		for _, foreignKey := range modelStruct.ForeignKeys {
+			if foreignKey.ModelType != modelStruct.ModelType {
+				foreignKeys = append(foreignKeys, foreignKey)
+			}
+		}
+	}
+	return
+}
+
 func (s *DB) print(v ...interface{}) {
 	s.logger.(logger).Print(v...)
 }
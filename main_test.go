@@ -1,19 +1,23 @@
 package gorm_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/erikstmartin/go-testdb"
 	"github.com/jinzhu/now"
 	"github.com/nkovacs/gorm"
+	_ "github.com/nkovacs/gorm/dialects/cockroach"
 	_ "github.com/nkovacs/gorm/dialects/mssql"
 	_ "github.com/nkovacs/gorm/dialects/mysql"
 	"github.com/nkovacs/gorm/dialects/postgres"
@@ -49,17 +53,23 @@ func OpenTestConnection() (db *gorm.DB, err error) {
 		db, err = gorm.Open("mysql", fmt.Sprintf("gorm:gorm@%v/gorm?charset=utf8&parseTime=True", dbhost))
 	case "postgres":
 		fmt.Println("testing postgres...")
-		dbhost := os.Getenv("GORM_DBHOST")
-		if dbhost != "" {
-			dbhost = fmt.Sprintf("host=%v ", dbhost)
-		}
-		db, err = gorm.Open("postgres", fmt.Sprintf("%vuser=gorm password=gorm DB.name=gorm sslmode=disable", dbhost))
+		db, err = gorm.Open("postgres", postgresTestSource(""))
 	case "foundation":
 		fmt.Println("testing foundation...")
 		db, err = gorm.Open("foundation", "dbname=gorm port=15432 sslmode=disable")
+	case "cockroach":
+		fmt.Println("testing cockroach...")
+		dbhost := os.Getenv("GORM_DBHOST")
+		if dbhost == "" {
+			dbhost = "localhost"
+		}
+		db, err = gorm.Open("cockroach", fmt.Sprintf("postgresql://root@%v:26257/defaultdb?sslmode=disable", dbhost))
 	case "mssql":
 		fmt.Println("testing mssql...")
 		db, err = gorm.Open("mssql", "server=SERVER_HERE;database=rogue;user id=USER_HERE;password=PW_HERE;port=1433")
+	case "sqlite-pure":
+		fmt.Println("testing sqlite-pure...")
+		db, err = gorm.Open("sqlite-pure", filepath.Join(os.TempDir(), "gorm_pure.db"))
 	default:
 		fmt.Println("testing sqlite3...")
 		db, err = gorm.Open("sqlite3", filepath.Join(os.TempDir(), "gorm.db"))
@@ -76,6 +86,15 @@ func OpenTestConnection() (db *gorm.DB, err error) {
 	return
 }
 
+// postgresTestSource data source of postgres test database, with extra connection parameters like `search_path=public`
+func postgresTestSource(params string) string {
+	dbhost := os.Getenv("GORM_DBHOST")
+	if dbhost != "" {
+		dbhost = fmt.Sprintf("host=%v ", dbhost)
+	}
+	return fmt.Sprintf("%vuser=gorm password=gorm DB.name=gorm sslmode=disable %v", dbhost, params)
+}
+
 func TestStringPrimaryKey(t *testing.T) {
 	type UUIDStruct struct {
 		ID   string `gorm:"primary_key"`
@@ -370,6 +389,217 @@ func TestTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionFunc(t *testing.T) {
+	defer DB.Where("name LIKE ?", "transaction-func%").Delete(&User{})
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&User{Name: "transaction-func-rollback"}).Error; err != nil {
+			t.Errorf("No error should raise, but got %v", err)
+		}
+		return errors.New("rollback")
+	})
+	if err == nil || err.Error() != "rollback" {
+		t.Errorf("Should return error of fc, but got %v", err)
+	}
+
+	if !DB.First(&User{}, "name = ?", "transaction-func-rollback").RecordNotFound() {
+		t.Errorf("Should not find record after rollback")
+	}
+
+	if err := DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Save(&User{Name: "transaction-func-commit"}).Error
+	}); err != nil {
+		t.Errorf("No error should raise, but got %v", err)
+	}
+
+	if err := DB.First(&User{}, "name = ?", "transaction-func-commit").Error; err != nil {
+		t.Errorf("Should be able to find committed record")
+	}
+}
+
+func TestQueriesWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	db := DB.WithContext(ctx)
+	if err := db.Find(&[]User{}).Error; err != context.Canceled {
+		t.Errorf("Query should be run with context, but got %v", err)
+	}
+
+	var count int
+	if err := db.Model(&User{}).Count(&count).Error; err != context.Canceled {
+		t.Errorf("Count should be run with context, but got %v", err)
+	}
+
+	if err := db.Create(&User{Name: "cancelled"}).Error; err != context.Canceled {
+		t.Errorf("Create should be run with context, but got %v", err)
+	}
+
+	if err := db.Where("name = ?", "cancelled").Delete(&User{}).Error; err != context.Canceled {
+		t.Errorf("Delete should be run with context, but got %v", err)
+	}
+
+	if tx := db.Begin(); tx.Error != context.Canceled {
+		t.Errorf("Transaction should be started with context, but got %v", tx.Error)
+	}
+}
+
+type serializationFailure struct{}
+
+func (serializationFailure) Error() string    { return "restart transaction" }
+func (serializationFailure) SQLState() string { return "40001" }
+
+func TestCockroachTransactionRetry(t *testing.T) {
+	defer testdb.Reset()
+
+	var statements []string
+	testdb.SetExecWithArgsFunc(func(query string, args []driver.Value) (driver.Result, error) {
+		statements = append(statements, query)
+		return driver.RowsAffected(0), nil
+	})
+
+	sqlDB, _ := sql.Open("testdb", "")
+	db, err := gorm.Open("cockroach", sqlDB)
+	if err != nil {
+		t.Fatalf("No error should happen when open cockroach with testdb, but got %+v", err)
+	}
+
+	var attempts int
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if attempts++; attempts < 3 {
+			return serializationFailure{}
+		}
+		return nil
+	}); err != nil {
+		t.Errorf("Transaction should succeed after retries, but got %v", err)
+	}
+
+	expected := []string{"SAVEPOINT cockroach_restart", "ROLLBACK TO SAVEPOINT cockroach_restart", "ROLLBACK TO SAVEPOINT cockroach_restart", "RELEASE SAVEPOINT cockroach_restart"}
+	if attempts != 3 || !reflect.DeepEqual(statements, expected) {
+		t.Errorf("Transaction should be retried from savepoint, but got %v attempts with %v", attempts, statements)
+	}
+
+	attempts, statements = 0, nil
+	if err := db.Set("gorm:transaction_max_retries", 2).Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return serializationFailure{}
+	}); err != (serializationFailure{}) {
+		t.Errorf("Should return retryable error after max retries, but got %v", err)
+	}
+
+	if attempts != 3 || len(statements) != 3 {
+		t.Errorf("Transaction should be retried 2 times, but got %v attempts with %v", attempts, statements)
+	}
+
+	attempts = 0
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		attempts++
+		return errors.New("rollback")
+	}); err == nil || attempts != 1 {
+		t.Errorf("Transaction shouldn't be retried with other errors, but got %v attempts with %v", attempts, err)
+	}
+}
+
+type namedDialect struct {
+	gorm.Dialect
+	name string
+}
+
+func (d namedDialect) GetName() string {
+	return d.name
+}
+
+func TestRegisterDialect(t *testing.T) {
+	gorm.RegisterDialect("gorm_test_value", namedDialect{Dialect: DB.Dialect(), name: "value"})
+	gorm.RegisterDialect("gorm_test_pointer", &namedDialect{Dialect: DB.Dialect(), name: "pointer"})
+
+	for name, expected := range map[string]string{"gorm_test_value": "value", "gorm_test_pointer": "pointer"} {
+		db, err := gorm.Open(name, DB.DB())
+		if err != nil {
+			t.Fatalf("No error should happen when open with registered dialect %v, but got %+v", name, err)
+		}
+
+		if db.Dialect().GetName() != expected {
+			t.Errorf("State of registered dialect should be kept, expected %v, but got %v", expected, db.Dialect().GetName())
+		}
+	}
+
+	if dialect, ok := gorm.GetDialect("gorm_test_pointer"); !ok || dialect.GetName() != "pointer" {
+		t.Errorf("Should get registered dialect, but got %v, %v", dialect, ok)
+	}
+
+	if _, ok := gorm.GetDialect("gorm_test_missing"); ok {
+		t.Errorf("Should not get dialect which isn't registered")
+	}
+}
+
+func TestOpenWithDialectConfig(t *testing.T) {
+	db, err := gorm.Open(DB.Dialect().GetName(), DB.DB(), gorm.DialectConfig{DefaultStringSize: 100, DefaultCollation: "NOCASE"})
+	if err != nil {
+		t.Fatalf("No error should happen when open with dialect config, but got %+v", err)
+	}
+
+	type ConfiguredDialect struct {
+		Name  string
+		Email string `sql:"size:50"`
+	}
+
+	field, _ := db.NewScope(&ConfiguredDialect{}).FieldByName("Name")
+	if sqlType := db.Dialect().DataTypeOf(field.StructField); !strings.Contains(sqlType, "(100)") || !strings.Contains(sqlType, "NOCASE") {
+		t.Errorf("Default string size and collation should be used, but got %v", sqlType)
+	}
+
+	field, _ = db.NewScope(&ConfiguredDialect{}).FieldByName("Email")
+	if sqlType := db.Dialect().DataTypeOf(field.StructField); !strings.Contains(sqlType, "(50)") || !strings.Contains(sqlType, "NOCASE") {
+		t.Errorf("Size of tag should be used, but got %v", sqlType)
+	}
+
+	field, _ = DB.NewScope(&ConfiguredDialect{}).FieldByName("Name")
+	if sqlType := DB.Dialect().DataTypeOf(field.StructField); strings.Contains(sqlType, "(100)") || strings.Contains(sqlType, "COLLATE") {
+		t.Errorf("Dialect of other DB shouldn't be changed, but got %v", sqlType)
+	}
+}
+
+func TestOpenWithConfig(t *testing.T) {
+	sqlDB := DB.DB()
+	fixedNow := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	db, err := gorm.OpenWith(DB.Dialect().GetName(), sqlDB, gorm.WithNowFunc(func() time.Time { return fixedNow }), gorm.WithPrepareStmt(true), gorm.WithSkipDefaultTransaction(true))
+	if err != nil {
+		t.Fatalf("No error should happen when open with config, but got %+v", err)
+	}
+	defer db.Where("name LIKE ?", "open_with_config%").Delete(&User{})
+
+	if db.DB() != sqlDB {
+		t.Errorf("DB should return the sql.DB it is opened with")
+	}
+
+	for i := 0; i < 2; i++ {
+		user := User{Name: fmt.Sprintf("open_with_config_%v", i)}
+		if err := db.Save(&user).Error; err != nil {
+			t.Errorf("No error should happen when save with prepared statements, but got %+v", err)
+		}
+
+		var found User
+		if err := db.First(&found, user.Id).Error; err != nil || found.Name != user.Name {
+			t.Errorf("Should find saved user with prepared statements, but got %+v, %+v", found, err)
+		}
+
+		if !found.CreatedAt.Equal(fixedNow) || !user.UpdatedAt.Equal(fixedNow) {
+			t.Errorf("NowFunc of config should be used, but got %v, %v", found.CreatedAt, user.UpdatedAt)
+		}
+	}
+
+	var user User
+	DB.Save(&User{Name: "open_with_config_default"}).First(&user, "name = ?", "open_with_config_default")
+	if user.CreatedAt.Equal(fixedNow) {
+		t.Errorf("NowFunc of config shouldn't be used by other DBs")
+	}
+
+	if _, err := gorm.Open(DB.Dialect().GetName(), &sql.Tx{}); err == nil {
+		t.Errorf("Should got error when open with unsupported connection")
+	}
+}
+
 func TestRow(t *testing.T) {
 	user1 := User{Name: "RowUser1", Age: 1, Birthday: now.MustParse("2000-1-1")}
 	user2 := User{Name: "RowUser2", Age: 10, Birthday: now.MustParse("2010-1-1")}
@@ -500,6 +730,35 @@ func TestRaw(t *testing.T) {
 	}
 }
 
+type UserWithRawEmails struct {
+	Id     int64
+	Name   string
+	Emails []Email `sql:"-"`
+}
+
+func (UserWithRawEmails) TableName() string {
+	return "users"
+}
+
+func (user *UserWithRawEmails) AfterFind(tx *gorm.DB) error {
+	return tx.Raw("SELECT * FROM emails WHERE user_id = ?", user.Id).Find(&user.Emails).Error
+}
+
+func TestRawInAfterFindOfFirst(t *testing.T) {
+	user := User{Name: "RawInAfterFindUser", Emails: []Email{{Email: "raw_in_after_find@example.com"}}}
+	DB.Save(&user)
+	defer DB.Delete(&user)
+
+	var got UserWithRawEmails
+	if err := DB.First(&got, user.Id).Error; err != nil {
+		t.Errorf("No error should happen when query raw sql in AfterFind of First, but got %v", err)
+	}
+
+	if len(got.Emails) != 1 || got.Emails[0].Email != user.Emails[0].Email {
+		t.Errorf("Should find emails with raw sql in AfterFind, but got %+v", got.Emails)
+	}
+}
+
 func TestGroup(t *testing.T) {
 	rows, err := DB.Select("name").Table("users").Group("name").Rows()
 
@@ -572,6 +831,144 @@ func TestJoinsWithSelect(t *testing.T) {
 	}
 }
 
+func TestJoinsRelations(t *testing.T) {
+	user := User{
+		Name:           "joins_relations",
+		CreditCard:     CreditCard{Number: "433333333333"},
+		BillingAddress: Address{Address1: "joins billing address"},
+		Company:        Company{Name: "joins company"},
+	}
+	DB.Save(&user)
+	DB.Save(&User{Name: "joins_relations_without_relations"})
+	defer DB.Where("name LIKE ?", "joins_relations%").Delete(&User{})
+
+	var users []User
+	if err := DB.Joins("CreditCard").Joins("BillingAddress").Where("users.name LIKE ?", "joins_relations%").Order("users.id").Find(&users).Error; err != nil {
+		t.Fatalf("No error should happen when joins relations, but got %+v", err)
+	}
+
+	if len(users) != 2 {
+		t.Fatalf("Should find two users with joined relations, but got %v", len(users))
+	}
+
+	if users[0].CreditCard.ID != user.CreditCard.ID || users[0].CreditCard.Number != "433333333333" {
+		t.Errorf("Has one relation should be loaded with join, but got %+v", users[0].CreditCard)
+	}
+
+	if users[0].BillingAddress.ID != user.BillingAddress.ID || users[0].BillingAddress.Address1 != "joins billing address" {
+		t.Errorf("Belongs to relation should be loaded with join, but got %+v", users[0].BillingAddress)
+	}
+
+	if users[1].CreditCard.ID != 0 || users[1].BillingAddress.ID != 0 {
+		t.Errorf("Relations should be blank if nothing joined, but got %+v, %+v", users[1].CreditCard, users[1].BillingAddress)
+	}
+
+	DB.Delete(&user.CreditCard)
+	var deletedCard User
+	DB.Joins("CreditCard").Joins("Company").Where("users.id = ?", user.Id).First(&deletedCard)
+	if deletedCard.CreditCard.ID != 0 {
+		t.Errorf("Soft deleted relation shouldn't be joined, but got %+v", deletedCard.CreditCard)
+	}
+	if deletedCard.Company.Name != "joins company" {
+		t.Errorf("Belongs to relation should be loaded with join, but got %+v", deletedCard.Company)
+	}
+}
+
+func TestJoinsPointerRelation(t *testing.T) {
+	type JoinsProfile struct {
+		ID   int
+		Name string
+	}
+
+	type JoinsAccount struct {
+		ID             int
+		Name           string
+		JoinsProfileID *int
+		JoinsProfile   *JoinsProfile
+	}
+
+	DB.DropTableIfExists(&JoinsAccount{}, &JoinsProfile{})
+	DB.AutoMigrate(&JoinsAccount{}, &JoinsProfile{})
+
+	DB.Save(&JoinsAccount{Name: "with_profile", JoinsProfile: &JoinsProfile{Name: "profile"}})
+	DB.Save(&JoinsAccount{Name: "without_profile"})
+
+	var accounts []JoinsAccount
+	if err := DB.Joins("JoinsProfile").Order("joins_accounts.id").Find(&accounts).Error; err != nil || len(accounts) != 2 {
+		t.Fatalf("Should find accounts with joined profile, but got %v, %+v", len(accounts), err)
+	}
+
+	if accounts[0].JoinsProfile == nil || accounts[0].JoinsProfile.Name != "profile" {
+		t.Errorf("Pointer relation should be loaded with join, but got %+v", accounts[0].JoinsProfile)
+	}
+
+	if accounts[1].JoinsProfile != nil {
+		t.Errorf("Pointer relation should be nil if nothing joined, but got %+v", accounts[1].JoinsProfile)
+	}
+}
+
+func TestJoinsByRelationName(t *testing.T) {
+	user1 := getPreparedUser("joins_by_name_1", "joins_by_name_a")
+	user2 := getPreparedUser("joins_by_name_2", "joins_by_name_b")
+	DB.Save(user1).Save(user2)
+	defer DB.Where("name LIKE ?", "joins_by_name_%").Delete(&User{})
+
+	var users []User
+	if err := DB.Where("Company.Name = ?", "joins_by_name_a").Find(&users).Error; err != nil {
+		t.Fatalf("No error should happen when query with relation's field, but got %+v", err)
+	}
+	if len(users) != 1 || users[0].Id != user1.Id || users[0].Name != "joins_by_name_1" {
+		t.Errorf("Should find user by company name, but got %+v", users)
+	}
+
+	var count int
+	DB.Model(&User{}).Where("users.name LIKE ?", "joins_by_name_%").Not("Company.Name = ?", "joins_by_name_a").Count(&count)
+	if count != 1 {
+		t.Errorf("Should count users not in company, but got %v", count)
+	}
+
+	users = nil
+	DB.Joins("Emails").Where("Emails.Email = ?", "user_joins_by_name_2@example1.com").Find(&users)
+	if len(users) != 1 || users[0].Id != user2.Id {
+		t.Errorf("Should find user by has many relation, but got %+v", users)
+	}
+
+	users = nil
+	DB.Joins("Languages").Where("Languages.Name IN (?)", []string{"lang_1_joins_by_name_1", "lang_2_joins_by_name_1"}).Find(&users)
+	if len(users) != 2 || users[0].Id != user1.Id || users[1].Id != user1.Id {
+		t.Errorf("Should find user by many to many relation for every matched language, but got %+v", users)
+	}
+
+	users = nil
+	DB.Where("Languages.Name IN (?)", []string{"lang_1_joins_by_name_1", "lang_2_joins_by_name_1"}).Find(&users)
+	if len(users) != 1 || users[0].Id != user1.Id {
+		t.Errorf("Should find user by many to many relation only once if it isn't joined, but got %+v", users)
+	}
+
+	DB.Model(&User{}).Where("users.name LIKE ?", "joins_by_name_%").Where("Emails.Email LIKE ?", "%joins_by_name_%").Count(&count)
+	if count != 2 {
+		t.Errorf("Should count users by has many relation only once, but got %v", count)
+	}
+
+	DB.Model(&User{}).Where("users.name LIKE ?", "joins_by_name_%").Not("Emails.Email = ?", "user_joins_by_name_2@example1.com").Count(&count)
+	if count != 1 {
+		t.Errorf("Should count users without matched has many relation, but got %v", count)
+	}
+
+	DB.Save(&User{Name: "Company.Name"})
+	DB.Model(&User{}).Where("users.name = 'Company.Name'").Count(&count)
+	if count != 1 {
+		t.Errorf("References in string literals shouldn't be replaced, but got %v", count)
+	}
+	DB.Where("name = ?", "Company.Name").Delete(&User{})
+
+	DB.Delete(&user1.Languages[0])
+	DB.Model(&User{}).Where("Languages.name = ?", "lang_1_joins_by_name_1").Count(&count)
+	if count != 0 {
+		t.Errorf("Soft deleted relations shouldn't be joined, but got %v", count)
+	}
+}
+
 func TestHaving(t *testing.T) {
 	rows, err := DB.Select("name, count(*) as total").Table("users").Group("name").Having("name IN (?)", []string{"2", "3"}).Rows()
 
@@ -596,10 +993,7 @@ func TestHaving(t *testing.T) {
 
 func DialectHasTzSupport() bool {
 	// NB: mssql and FoundationDB do not support time zones.
-	if dialect := os.Getenv("GORM_DIALECT"); dialect == "mssql" || dialect == "foundation" {
-		return false
-	}
-	return true
+	return DB.Dialect().Capabilities().TimeZones
 }
 
 func TestTimeWithZone(t *testing.T) {
@@ -648,7 +1042,7 @@ func TestHstore(t *testing.T) {
 		Bulk postgres.Hstore
 	}
 
-	if dialect := os.Getenv("GORM_DIALECT"); dialect != "postgres" {
+	if DB.Dialect().GetName() != "postgres" {
 		t.Skip()
 	}
 
@@ -702,6 +1096,32 @@ func TestSetAndGet(t *testing.T) {
 	}
 }
 
+func TestMssqlCountWithLimit(t *testing.T) {
+	defer testdb.Reset()
+
+	var queries []string
+	testdb.SetQueryWithArgsFunc(func(query string, args []driver.Value) (driver.Rows, error) {
+		queries = append(queries, query)
+		return testdb.RowsFromCSVString([]string{"count"}, "3"), nil
+	})
+
+	sqlDB, _ := sql.Open("testdb", "")
+	db, err := gorm.Open("mssql", sqlDB)
+	if err != nil {
+		t.Fatalf("No error should happen when open mssql with testdb, but got %+v", err)
+	}
+
+	var count int
+	if err := db.Model(&User{}).Order("name").Limit(10).Offset(5).Count(&count).Error; err != nil || count != 3 {
+		t.Errorf("No error should happen when count with limit, but got %v, %+v", count, err)
+	}
+
+	expected := `SELECT count(*) FROM "users"   ORDER BY (SELECT NULL) OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY`
+	if len(queries) != 1 || queries[0] != expected {
+		t.Errorf("Counting query should be ordered for OFFSET and FETCH NEXT, but got %v", queries)
+	}
+}
+
 func TestCompatibilityMode(t *testing.T) {
 	DB, _ := gorm.Open("testdb", "")
 	testdb.SetQueryFunc(func(query string) (driver.Rows, error) {
@@ -723,9 +1143,7 @@ func TestCompatibilityMode(t *testing.T) {
 
 func TestOpenExistingDB(t *testing.T) {
 	DB.Save(&User{Name: "jnfeinstein"})
-	dialect := os.Getenv("GORM_DIALECT")
-
-	db, err := gorm.Open(dialect, DB.DB())
+	db, err := gorm.Open(DB.Dialect().GetName(), DB.DB())
 	if err != nil {
 		t.Errorf("Should have wrapped the existing DB connection")
 	}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -432,3 +434,458 @@ func TestMultipleIndexes(t *testing.T) {
 		t.Error("MultipleIndexes unique index failed")
 	}
 }
+
+type ConstraintCity struct {
+	ID   int64
+	Name string
+}
+
+type ConstraintGroup struct {
+	ID   int64
+	Name string
+}
+
+type ConstraintAccount struct {
+	ID               int64
+	ConstraintUserID int64
+}
+
+type ConstraintUser struct {
+	ID               int64
+	ConstraintCityID int64
+	ConstraintCity   ConstraintCity      `gorm:"constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+	Accounts         []ConstraintAccount `gorm:"constraint:OnDelete:CASCADE"`
+	Groups           []ConstraintGroup   `gorm:"many2many:constraint_user_groups;constraint"`
+}
+
+func TestForeignKeyConstraints(t *testing.T) {
+	DB.DropTableIfExists("constraint_user_groups", &ConstraintAccount{}, &ConstraintUser{}, &ConstraintGroup{}, &ConstraintCity{})
+	if err := DB.AutoMigrate(&ConstraintCity{}, &ConstraintGroup{}, &ConstraintUser{}, &ConstraintAccount{}).Error; err != nil {
+		t.Errorf("No error should happen when auto migrate tables with constraints, but got %+v", err)
+	}
+
+	dialect := DB.Dialect()
+	for _, foreignKey := range []struct{ table, columns, dest string }{
+		{"constraint_users", "constraint_city_id", "constraint_cities(id)"},
+		{"constraint_accounts", "constraint_user_id", "constraint_users(id)"},
+		{"constraint_user_groups", "constraint_user_id", "constraint_users(id)"},
+		{"constraint_user_groups", "constraint_group_id", "constraint_groups(id)"},
+	} {
+		if keyName := dialect.BuildForeignKeyName(foreignKey.table, foreignKey.columns, foreignKey.dest); !dialect.HasForeignKey(foreignKey.table, keyName) {
+			t.Errorf("%v should have foreign key %v", foreignKey.table, keyName)
+		}
+	}
+
+	if err := DB.AutoMigrate(&ConstraintCity{}, &ConstraintGroup{}, &ConstraintUser{}, &ConstraintAccount{}).Error; err != nil {
+		t.Errorf("No error should happen when auto migrate tables with existing constraints, but got %+v", err)
+	}
+
+	if foreignKeys := DB.NewScope(&ConstraintAccount{}).GetModelStruct().ForeignKeys; len(foreignKeys) != 0 {
+		t.Errorf("ConstraintAccount's model struct shouldn't be changed by ConstraintUser's relations, but got %+v", foreignKeys)
+	}
+
+	var accountForeignKeys []*gorm.ForeignKey
+	for _, foreignKey := range DB.NewScope(&ConstraintUser{}).GetModelStruct().ForeignKeys {
+		if foreignKey.ModelType == reflect.TypeOf(ConstraintAccount{}) {
+			accountForeignKeys = append(accountForeignKeys, foreignKey)
+		}
+	}
+	if len(accountForeignKeys) != 1 || accountForeignKeys[0].OnDelete != "CASCADE" || accountForeignKeys[0].OnUpdate != "" {
+		t.Errorf("ConstraintUser should have foreign key of its has many relation on ConstraintAccount, but got %+v", accountForeignKeys)
+	}
+
+	city := ConstraintCity{Name: "Berlin"}
+	DB.Save(&city)
+	user := ConstraintUser{ConstraintCityID: city.ID, Accounts: []ConstraintAccount{{}}, Groups: []ConstraintGroup{{Name: "admin"}}}
+	if err := DB.Save(&user).Error; err != nil {
+		t.Errorf("No error should happen when save record with foreign keys, but got %+v", err)
+	}
+}
+
+type IndexOption struct {
+	ID        int64
+	Name      string `sql:"index:idx_index_options_age_name,priority:2;check:name_checker,name <> ''"`
+	Age       int64  `sql:"index:idx_index_options_age_name,priority:1,sort:desc;check:age >= 0"`
+	Email     string `sql:"unique_index:uix_index_options_lower_email,expression:lower(email)"`
+	Code      string `sql:"unique_index:uix_index_options_code,where:code <> ''"`
+	DeletedAt *time.Time
+}
+
+func TestIndexOptionsAndChecks(t *testing.T) {
+	DB.DropTableIfExists(&IndexOption{})
+
+	if !DB.Dialect().Capabilities().PartialIndexes {
+		t.Skip("mysql doesn't support partial indexes")
+	}
+
+	modelStruct := DB.NewScope(&IndexOption{}).GetModelStruct()
+	if len(modelStruct.Indexes) != 3 || len(modelStruct.Checks) != 2 {
+		t.Fatalf("IndexOption should have 3 indexes and 2 checks, but got %v, %v", len(modelStruct.Indexes), len(modelStruct.Checks))
+	}
+
+	index := modelStruct.Indexes[0]
+	if index.Name != "idx_index_options_age_name" || len(index.Fields) != 2 || index.Fields[0].Name != "Age" || index.Fields[0].Sort != "DESC" || index.Fields[1].Name != "Name" {
+		t.Errorf("Composite index's columns should be ordered by priority, but got %+v", index)
+	}
+
+	if check := modelStruct.Checks[0]; check.Name != "name_checker" || check.Constraint != "name <> ''" {
+		t.Errorf("Check constraint should be parsed with its name, but got %+v", check)
+	}
+
+	if err := DB.AutoMigrate(&IndexOption{}).Error; err != nil {
+		t.Errorf("No error should happen when auto migrate, but got %+v", err)
+	}
+
+	scope := DB.NewScope(&IndexOption{})
+	for _, name := range []string{"idx_index_options_age_name", "uix_index_options_lower_email", "uix_index_options_code"} {
+		if !scope.Dialect().HasIndex(scope.TableName(), name) {
+			t.Errorf("Failed to create index %v", name)
+		}
+	}
+
+	for _, name := range []string{"name_checker", "chk_index_options_age"} {
+		if !scope.Dialect().(gorm.ConstraintDialect).HasConstraint(scope.TableName(), name) {
+			t.Errorf("Failed to create check constraint %v", name)
+		}
+	}
+
+	if err := DB.AutoMigrate(&IndexOption{}).Error; err != nil {
+		t.Errorf("No error should happen when auto migrate again, but got %+v", err)
+	}
+
+	if err := DB.Save(&IndexOption{Name: "jinzhu", Age: 18, Email: "jinzhu@example.org"}).Error; err != nil {
+		t.Errorf("No error should happen when save valid record, but got %+v", err)
+	}
+
+	if err := DB.Save(&IndexOption{Name: "jinzhu2", Age: 18, Email: "Jinzhu@Example.org"}).Error; err == nil {
+		t.Errorf("Should get error when save duplicated email with expression index")
+	}
+
+	if err := DB.Save(&IndexOption{Name: "jinzhu3", Age: 18, Email: "jinzhu3@example.org"}).Error; err != nil {
+		t.Errorf("Blank codes should be excluded from partial unique index, but got %+v", err)
+	}
+
+	if err := DB.Save(&IndexOption{Name: "", Age: 18, Email: "blank@example.org"}).Error; err == nil {
+		t.Errorf("Should get error when save record violating check constraint")
+	}
+
+	if err := DB.Save(&IndexOption{Name: "jinzhu4", Age: -1, Email: "negative@example.org"}).Error; err == nil {
+		t.Errorf("Should get error when save record violating check constraint")
+	}
+}
+
+type CommentedUser struct {
+	ID   int64
+	Name string `sql:"comment:user's full name"`
+	Age  int64  `sql:"comment:age in years"`
+}
+
+func (CommentedUser) TableComment() string {
+	return "registered users"
+}
+
+func TestComments(t *testing.T) {
+	dialect, ok := DB.Dialect().(gorm.CommentDialect)
+	if !ok {
+		t.Skip("sqlite doesn't support comments")
+	}
+
+	DB.DropTableIfExists(&CommentedUser{})
+	if err := DB.AutoMigrate(&CommentedUser{}).Error; err != nil {
+		t.Errorf("No error should happen when create table with comments, but got %+v", err)
+	}
+
+	scope := DB.NewScope(&CommentedUser{})
+	if comment := dialect.Comment(scope.TableName(), ""); comment != "registered users" {
+		t.Errorf("Table comment should be created, but got %v", comment)
+	}
+
+	if comment := dialect.Comment(scope.TableName(), "name"); comment != "user's full name" {
+		t.Errorf("Column comment should be created, but got %v", comment)
+	}
+
+	DB.Exec(dialect.CommentSQL(scope.TableName(), nil, "outdated"))
+	if field, ok := scope.FieldByName("Age"); ok {
+		DB.Exec(dialect.CommentSQL(scope.TableName(), field.StructField, "outdated"))
+	}
+
+	if err := DB.AutoMigrate(&CommentedUser{}).Error; err != nil {
+		t.Errorf("No error should happen when update comments, but got %+v", err)
+	}
+
+	if comment := dialect.Comment(scope.TableName(), ""); comment != "registered users" {
+		t.Errorf("Table comment should be updated, but got %v", comment)
+	}
+
+	if comment := dialect.Comment(scope.TableName(), "age"); comment != "age in years" {
+		t.Errorf("Column comment should be updated, but got %v", comment)
+	}
+}
+
+type IntrospectedProduct struct {
+	ID    int64
+	Code  string  `sql:"size:32;unique"`
+	Name  string  `sql:"size:100;not null;index:idx_introspected_products_name"`
+	Price float64 `sql:"default:1.5"`
+}
+
+func TestIntrospection(t *testing.T) {
+	DB.DropTableIfExists("constraint_user_groups", &ConstraintAccount{}, &ConstraintUser{}, &ConstraintGroup{}, &ConstraintCity{}, &IntrospectedProduct{})
+	if err := DB.AutoMigrate(&IntrospectedProduct{}, &ConstraintCity{}, &ConstraintGroup{}, &ConstraintUser{}, &ConstraintAccount{}).Error; err != nil {
+		t.Fatalf("No error should happen when auto migrate tables, but got %+v", err)
+	}
+
+	dialect, ok := DB.Dialect().(gorm.IntrospectionDialect)
+	if !ok {
+		t.Fatalf("%v should be able to read schema of database", DB.Dialect().GetName())
+	}
+
+	tables, err := dialect.Tables()
+	if err != nil {
+		t.Fatalf("No error should happen when list tables, but got %+v", err)
+	}
+
+	for _, tableName := range []string{"introspected_products", "constraint_users", "constraint_user_groups"} {
+		var found bool
+		for _, table := range tables {
+			found = found || table == tableName
+		}
+		if !found {
+			t.Errorf("Tables should include %v, but got %+v", tableName, tables)
+		}
+	}
+
+	columnTypes, err := dialect.ColumnTypes("introspected_products")
+	if err != nil {
+		t.Fatalf("No error should happen when read column types, but got %+v", err)
+	}
+
+	columns := map[string]gorm.ColumnType{}
+	for _, columnType := range columnTypes {
+		columns[columnType.Name] = columnType
+	}
+
+	if id := columns["id"]; !id.PrimaryKey || !id.AutoIncrement {
+		t.Errorf("id should be auto increment primary key, but got %+v", id)
+	}
+
+	if code := columns["code"]; !code.Unique || code.Length != 32 || code.PrimaryKey {
+		t.Errorf("code should be unique column with length 32, but got %+v", code)
+	}
+
+	if name := columns["name"]; name.Nullable || name.Unique || name.Length != 100 {
+		t.Errorf("name should be not null column with length 100, but got %+v", name)
+	}
+
+	if price := columns["price"]; !price.Default.Valid || !strings.Contains(price.Default.String, "1.5") {
+		t.Errorf("price should have default value, but got %+v", price)
+	}
+
+	indexes, err := dialect.Indexes("introspected_products")
+	if err != nil {
+		t.Fatalf("No error should happen when read indexes, but got %+v", err)
+	}
+
+	var foundIndex bool
+	for _, index := range indexes {
+		if index.Name == "idx_introspected_products_name" {
+			foundIndex = true
+			if index.Unique || index.Primary || len(index.Columns) != 1 || index.Columns[0] != "name" {
+				t.Errorf("idx_introspected_products_name should be index on name, but got %+v", index)
+			}
+		}
+	}
+	if !foundIndex {
+		t.Errorf("Indexes should include idx_introspected_products_name, but got %+v", indexes)
+	}
+
+	foreignKeys, err := dialect.ForeignKeys("constraint_users")
+	if err != nil {
+		t.Fatalf("No error should happen when read foreign keys, but got %+v", err)
+	}
+
+	keyName := DB.Dialect().BuildForeignKeyName("constraint_users", "constraint_city_id", "constraint_cities(id)")
+	if len(foreignKeys) != 1 {
+		t.Fatalf("constraint_users should have one foreign key, but got %+v", foreignKeys)
+	}
+
+	if foreignKey := foreignKeys[0]; foreignKey.Name != keyName || foreignKey.ReferencedTable != "constraint_cities" ||
+		!reflect.DeepEqual(foreignKey.Columns, []string{"constraint_city_id"}) || !reflect.DeepEqual(foreignKey.ReferencedColumns, []string{"id"}) ||
+		foreignKey.OnDelete != "CASCADE" || foreignKey.OnUpdate != "CASCADE" {
+		t.Errorf("constraint_users should have foreign key %v referencing constraint_cities, but got %+v", keyName, foreignKey)
+	}
+
+	if foreignKeys, err := dialect.ForeignKeys("constraint_user_groups"); err != nil || len(foreignKeys) != 2 {
+		t.Errorf("constraint_user_groups should have two foreign keys, but got %+v, %+v", foreignKeys, err)
+	}
+}
+
+func TestModifyColumn(t *testing.T) {
+	type ModifiedColumn struct {
+		ID   int
+		Name string `sql:"size:50"`
+	}
+
+	DB.DropTableIfExists(&ModifiedColumn{})
+	if err := DB.AutoMigrate(&ModifiedColumn{}).Error; err != nil {
+		t.Fatalf("No error should happen when migrate, but got %+v", err)
+	}
+
+	err := DB.Model(&ModifiedColumn{}).ModifyColumn("name", "varchar(100)").Error
+	if DB.Dialect().Capabilities().AlterColumn == gorm.AlterColumnUnsupported {
+		if err == nil {
+			t.Errorf("Should got error when dialect doesn't support modifying columns")
+		}
+		return
+	}
+
+	if err != nil {
+		t.Errorf("No error should happen when modify column, but got %+v", err)
+	}
+}
+
+type prefixedColumnNamingStrategy struct {
+	gorm.DefaultNamingStrategy
+}
+
+func (prefixedColumnNamingStrategy) ColumnName(fieldName string) string {
+	return "col_" + gorm.ToDBName(fieldName)
+}
+
+type NamingUser struct {
+	ID        int
+	UserName  string           `sql:"index"`
+	Languages []NamingLanguage `gorm:"many2many:naming_user_languages"`
+}
+
+type NamingLanguage struct {
+	ID   int
+	Name string
+}
+
+func TestNamingStrategy(t *testing.T) {
+	db, err := gorm.OpenWith(DB.Dialect().GetName(), DB.DB(), gorm.WithNamingStrategy(prefixedColumnNamingStrategy{gorm.DefaultNamingStrategy{TablePrefix: "t_"}}))
+	if err != nil {
+		t.Fatalf("No error should happen when open with naming strategy, but got %+v", err)
+	}
+
+	db.DropTableIfExists(&NamingUser{}, &NamingLanguage{}, "t_naming_user_languages")
+	if err := db.AutoMigrate(&NamingUser{}, &NamingLanguage{}).Error; err != nil {
+		t.Fatalf("No error should happen when migrate with naming strategy, but got %+v", err)
+	}
+	defer db.DropTableIfExists(&NamingUser{}, &NamingLanguage{}, "t_naming_user_languages")
+
+	dialect := db.Dialect()
+	if !dialect.HasTable("t_naming_users") || !dialect.HasTable("t_naming_languages") || !dialect.HasTable("t_naming_user_languages") {
+		t.Errorf("Tables should be named with naming strategy")
+	}
+
+	if !dialect.HasColumn("t_naming_users", "col_user_name") || !dialect.HasColumn("t_naming_user_languages", "col_naming_user_col_id") {
+		t.Errorf("Columns should be named with naming strategy")
+	}
+
+	if !dialect.HasIndex("t_naming_users", "idx_t_naming_users_col_user_name") {
+		t.Errorf("Index should be named with naming strategy")
+	}
+
+	user := NamingUser{UserName: "naming", Languages: []NamingLanguage{{Name: "EN"}, {Name: "ZH"}}}
+	if err := db.Save(&user).Error; err != nil {
+		t.Errorf("No error should happen when save with naming strategy, but got %+v", err)
+	}
+
+	var found NamingUser
+	if err := db.Preload("Languages").Where(&NamingUser{UserName: "naming"}).First(&found).Error; err != nil || len(found.Languages) != 2 {
+		t.Errorf("Should find user with languages, but got %+v, %+v", found, err)
+	}
+
+	if tableName := DB.NewScope(&NamingUser{}).TableName(); tableName != "naming_users" {
+		t.Errorf("Naming strategy of other DBs shouldn't be used, but got %v", tableName)
+	}
+
+	if field, _ := DB.NewScope(&NamingUser{}).FieldByName("UserName"); field.DBName != "user_name" {
+		t.Errorf("Naming strategy of other DBs shouldn't be used, but got %v", field.DBName)
+	}
+}
+
+func TestSetNamingStrategyConcurrently(t *testing.T) {
+	db, err := gorm.OpenWith(DB.Dialect().GetName(), DB.DB())
+	if err != nil {
+		t.Fatalf("No error should happen when open DB, but got %+v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			db.SetNamingStrategy(gorm.DefaultNamingStrategy{TablePrefix: "t_"})
+			db.NewScope(&NamingUser{}).TableName()
+		}()
+	}
+	wg.Wait()
+
+	if tableName := db.NewScope(&NamingUser{}).TableName(); tableName != "t_naming_users" {
+		t.Errorf("Table should be named with naming strategy, but got %v", tableName)
+	}
+}
+
+type SchemaEvent struct {
+	ID   int
+	Name string `sql:"index"`
+}
+
+func TestSchemaQualifiedTable(t *testing.T) {
+	dialect := DB.Dialect()
+	if quoted := DB.Table("gorm_reporting.schema_events").NewScope(&SchemaEvent{}).QuotedTableName(); quoted != dialect.Quote("gorm_reporting")+"."+dialect.Quote("schema_events") {
+		t.Errorf("Every part of schema-qualified table name should be quoted, but got %v", quoted)
+	}
+
+	if DB.Dialect().GetName() != "postgres" {
+		t.Skip("Skipping this because only postgres has schemas")
+	}
+
+	if err := DB.Exec("CREATE SCHEMA IF NOT EXISTS gorm_reporting").Error; err != nil {
+		t.Fatalf("No error should happen when create schema, but got %+v", err)
+	}
+
+	tableName := "gorm_reporting.schema_events"
+	DB.DropTableIfExists(tableName)
+	defer DB.DropTableIfExists(tableName)
+	for i := 0; i < 2; i++ {
+		if err := DB.Table(tableName).AutoMigrate(&SchemaEvent{}).Error; err != nil {
+			t.Fatalf("No error should happen when migrate schema-qualified table, but got %+v", err)
+		}
+	}
+
+	if !dialect.HasTable(tableName) || dialect.HasTable("schema_events") {
+		t.Errorf("Table should be found in its schema only")
+	}
+
+	if !dialect.HasColumn(tableName, "name") || !dialect.HasIndex(tableName, "idx_schema_events_name") {
+		t.Errorf("Columns and indexes should be found in table's schema")
+	}
+
+	if columnTypes, err := dialect.(gorm.IntrospectionDialect).ColumnTypes(tableName); err != nil || len(columnTypes) != 2 {
+		t.Errorf("Column types should be read from table's schema, but got %+v, %+v", columnTypes, err)
+	}
+
+	if err := DB.Table(tableName).Create(&SchemaEvent{Name: "schema"}).Error; err != nil {
+		t.Errorf("No error should happen when create in schema-qualified table, but got %+v", err)
+	}
+
+	var event SchemaEvent
+	if err := DB.Table(tableName).Where("name = ?", "schema").First(&event).Error; err != nil || event.ID == 0 {
+		t.Errorf("Should find record in schema-qualified table, but got %+v, %+v", event, err)
+	}
+
+	searchPathDB, err := gorm.Open("postgres", postgresTestSource("search_path=gorm_reporting,public"))
+	if err != nil {
+		t.Fatalf("No error should happen when open with search_path, but got %+v", err)
+	}
+	defer searchPathDB.Close()
+
+	searchPathDialect := searchPathDB.Dialect()
+	if !searchPathDialect.HasTable("schema_events") || !searchPathDialect.HasColumn("schema_events", "name") || !searchPathDialect.HasIndex("schema_events", "idx_schema_events_name") {
+		t.Errorf("Unqualified table should be found with search_path")
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"errors"
 	"go/ast"
 	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/jinzhu/inflection"
 )
 
 // DefaultTableNameHandler default table name handler
@@ -44,6 +45,9 @@ var modelStructsMap = newModelStructsMap()
 type ModelStruct struct {
 	PrimaryFields    []*StructField
 	StructFields     []*StructField
+	ForeignKeys      []*ForeignKey
+	Indexes          []*Index
+	Checks           []*Check
 	ModelType        reflect.Type
 	defaultTableName string
 }
@@ -106,6 +110,188 @@ type Relationship struct {
 	JoinTableHandler             JoinTableHandlerInterface
 }
 
+// ForeignKey described a foreign key constraint generated from a relationship with tag `constraint`, it belongs to the model
+// declaring the relationship, ModelType is the model whose table holds the foreign key columns, which is another model for has one, has many relations
+type ForeignKey struct {
+	Field              *StructField
+	ModelType          reflect.Type
+	DBNames            []string
+	AssociationType    reflect.Type
+	AssociationDBNames []string
+	OnDelete           string
+	OnUpdate           string
+}
+
+// addForeignKey add foreign key to model struct if it isn't defined yet
+func (s *ModelStruct) addForeignKey(foreignKey *ForeignKey) {
+	for _, fk := range s.ForeignKeys {
+		if fk.ModelType == foreignKey.ModelType && fk.AssociationType == foreignKey.AssociationType && strings.Join(fk.DBNames, ",") == strings.Join(foreignKey.DBNames, ",") {
+			return
+		}
+	}
+	s.ForeignKeys = append(s.ForeignKeys, foreignKey)
+}
+
+// newForeignKey build foreign key from relationship field's tag `constraint`, e.g:
+//     Company Company `gorm:"constraint:OnDelete:CASCADE,OnUpdate:SET NULL"`
+func newForeignKey(field *StructField, modelType reflect.Type, dbNames []string, associationType reflect.Type, associationDBNames []string) *ForeignKey {
+	setting, ok := field.TagSettings["CONSTRAINT"]
+	if !ok || field.Relationship == nil || field.Relationship.PolymorphicType != "" {
+		return nil
+	}
+
+	foreignKey := &ForeignKey{
+		Field:              field,
+		ModelType:          modelType,
+		DBNames:            dbNames,
+		AssociationType:    associationType,
+		AssociationDBNames: associationDBNames,
+	}
+
+	for _, str := range strings.Split(setting, ",") {
+		if v := strings.SplitN(str, ":", 2); len(v) == 2 {
+			switch strings.ToUpper(strings.TrimSpace(v[0])) {
+			case "ONDELETE":
+				foreignKey.OnDelete = strings.TrimSpace(v[1])
+			case "ONUPDATE":
+				foreignKey.OnUpdate = strings.TrimSpace(v[1])
+			}
+		}
+	}
+	return foreignKey
+}
+
+// Index described an index defined with tag `index` or `unique_index`, an empty Name means the default name,
+// which is built from table name and column name, e.g. `idx_users_name`, `uix_users_email`
+type Index struct {
+	Name   string
+	Unique bool
+	Using  string
+	Where  string
+	Fields []*IndexField
+}
+
+// IndexField described a column or an expression of an index
+type IndexField struct {
+	*StructField
+	Expression string
+	Sort       string
+	Priority   int
+}
+
+// Check described a check constraint defined with tag `check`, an empty Name means the default name,
+// which is built from table name and column name, e.g. `chk_users_age`
+type Check struct {
+	Name       string
+	Constraint string
+	Field      *StructField
+}
+
+var checkNameRegexp = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// parseIndexes parse indexes and check constraints from fields' tags, e.g:
+//     Name  string `sql:"index:idx_name_age,sort:desc;check:name <> ''"`
+//     Age   int    `sql:"index:idx_name_age,priority:1;check:age_checker,age > 13"`
+//     Email string `sql:"unique_index:uix_lower_email,expression:lower(email),where:deleted_at IS NULL,using:btree"`
+func (s *ModelStruct) parseIndexes() {
+	var indexes = map[string]*Index{}
+
+	for _, field := range s.StructFields {
+		if field.IsIgnored {
+			continue
+		}
+
+		for _, key := range []string{"INDEX", "UNIQUE_INDEX"} {
+			setting, ok := field.TagSettings[key]
+			if !ok {
+				continue
+			}
+
+			var (
+				index      *Index
+				indexField *IndexField
+			)
+
+			getIndex := func(name string) {
+				if name == key {
+					name = ""
+				}
+
+				indexKey := key + ":" + name
+				if name == "" {
+					indexKey += ":" + field.DBName
+				}
+
+				if index = indexes[indexKey]; index == nil {
+					index = &Index{Name: name, Unique: key == "UNIQUE_INDEX"}
+					indexes[indexKey] = index
+					s.Indexes = append(s.Indexes, index)
+				}
+				indexField = &IndexField{StructField: field, Priority: 10}
+				index.Fields = append(index.Fields, indexField)
+			}
+
+			for idx, str := range splitTagValue(setting) {
+				str = strings.TrimSpace(str)
+				if v := strings.SplitN(str, ":", 2); len(v) == 2 {
+					if idx == 0 {
+						getIndex("")
+					}
+
+					value := strings.TrimSpace(v[1])
+					switch strings.ToUpper(strings.TrimSpace(v[0])) {
+					case "PRIORITY":
+						indexField.Priority, _ = strconv.Atoi(value)
+					case "SORT":
+						indexField.Sort = strings.ToUpper(value)
+					case "EXPRESSION":
+						indexField.Expression = value
+					case "USING":
+						index.Using = value
+					case "WHERE":
+						index.Where = value
+					}
+				} else {
+					getIndex(str)
+				}
+			}
+		}
+
+		if constraint, ok := field.TagSettings["CHECK"]; ok {
+			check := &Check{Constraint: constraint, Field: field}
+			if v := strings.SplitN(constraint, ",", 2); len(v) == 2 && checkNameRegexp.MatchString(strings.TrimSpace(v[0])) {
+				check.Name, check.Constraint = strings.TrimSpace(v[0]), strings.TrimSpace(v[1])
+			}
+			s.Checks = append(s.Checks, check)
+		}
+	}
+
+	for _, index := range s.Indexes {
+		sort.SliceStable(index.Fields, func(i, j int) bool {
+			return index.Fields[i].Priority < index.Fields[j].Priority
+		})
+	}
+}
+
+// splitTagValue split tag value with comma, but commas inside parentheses are kept, e.g. `expression:coalesce(a,b)`
+func splitTagValue(value string) (results []string) {
+	var depth, start int
+	for i, c := range value {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				results = append(results, value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(results, value[start:])
+}
+
 func getForeignField(column string, fields []*StructField) *StructField {
 	for _, field := range fields {
 		if field.Name == column || field.DBName == column || field.DBName == ToDBName(column) {
@@ -133,22 +319,26 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 		return &modelStruct
 	}
 
-	// Get Cached model struct
-	if value := modelStructsMap.Get(reflectType); value != nil {
+	// Get Cached model struct, DBs with naming strategy have their own cache
+	modelStructs := modelStructsMap
+	if scope.db != nil {
+		if _, customModelStructs := scope.db.customNaming(); customModelStructs != nil {
+			modelStructs = customModelStructs
+		}
+	}
+
+	if value := modelStructs.Get(reflectType); value != nil {
 		return value
 	}
 
 	modelStruct.ModelType = reflectType
+	namingStrategy := scope.namingStrategy()
 
 	// Set default table name
 	if tabler, ok := reflect.New(reflectType).Interface().(tabler); ok {
 		modelStruct.defaultTableName = tabler.TableName()
 	} else {
-		tableName := ToDBName(reflectType.Name())
-		if scope.db == nil || !scope.db.parent.singularTable {
-			tableName = inflection.Plural(tableName)
-		}
-		modelStruct.defaultTableName = tableName
+		modelStruct.defaultTableName = namingStrategy.TableName(reflectType.Name())
 	}
 
 	// Get all fields
@@ -162,9 +352,11 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 				TagSettings: parseTagSetting(fieldStruct.Tag),
 			}
 
-			// is ignored field
+			// is ignored field, count fields of relations are assigned by `PreloadCount`
 			if _, ok := field.TagSettings["-"]; ok {
 				field.IsIgnored = true
+			} else if _, ok := field.TagSettings["COUNT"]; ok {
+				field.IsIgnored = true
 			} else {
 				if _, ok := field.TagSettings["PRIMARY_KEY"]; ok {
 					field.IsPrimaryKey = true
@@ -253,7 +445,7 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 											// source foreign keys (db names)
 											relationship.ForeignFieldNames = append(relationship.ForeignFieldNames, foreignField.DBName)
 											// join table foreign keys for source
-											joinTableDBName := ToDBName(reflectType.Name()) + "_" + foreignField.DBName
+											joinTableDBName := namingStrategy.ColumnName(reflectType.Name()) + "_" + foreignField.DBName
 											relationship.ForeignDBNames = append(relationship.ForeignDBNames, joinTableDBName)
 										}
 									}
@@ -270,13 +462,13 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 											// association foreign keys (db names)
 											relationship.AssociationForeignFieldNames = append(relationship.AssociationForeignFieldNames, field.DBName)
 											// join table foreign keys for association
-											joinTableDBName := ToDBName(elemType.Name()) + "_" + field.DBName
+											joinTableDBName := namingStrategy.ColumnName(elemType.Name()) + "_" + field.DBName
 											relationship.AssociationForeignDBNames = append(relationship.AssociationForeignDBNames, joinTableDBName)
 										}
 									}
 
 									joinTableHandler := JoinTableHandler{}
-									joinTableHandler.Setup(relationship, many2many, reflectType, elemType)
+									joinTableHandler.Setup(relationship, namingStrategy.JoinTableName(many2many), reflectType, elemType)
 									relationship.JoinTableHandler = &joinTableHandler
 									field.Relationship = relationship
 								} else {
@@ -350,6 +542,9 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 
 									if len(relationship.ForeignFieldNames) != 0 {
 										field.Relationship = relationship
+										if foreignKey := newForeignKey(field, elemType, relationship.ForeignDBNames, reflectType, relationship.AssociationForeignDBNames); foreignKey != nil {
+											modelStruct.addForeignKey(foreignKey)
+										}
 									}
 								}
 							} else {
@@ -448,6 +643,9 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 							if len(relationship.ForeignFieldNames) != 0 {
 								relationship.Kind = "has_one"
 								field.Relationship = relationship
+								if foreignKey := newForeignKey(field, toScope.GetModelStruct().ModelType, relationship.ForeignDBNames, reflectType, relationship.AssociationForeignDBNames); foreignKey != nil {
+									modelStruct.addForeignKey(foreignKey)
+								}
 							} else {
 								var foreignKeys = tagForeignKeys
 								var associationForeignKeys = tagAssociationForeignKeys
@@ -507,6 +705,9 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 								if len(relationship.ForeignFieldNames) != 0 {
 									relationship.Kind = "belongs_to"
 									field.Relationship = relationship
+									if foreignKey := newForeignKey(field, reflectType, relationship.ForeignDBNames, toScope.GetModelStruct().ModelType, relationship.AssociationForeignDBNames); foreignKey != nil {
+										modelStruct.addForeignKey(foreignKey)
+									}
 								}
 							}
 						}(field)
@@ -520,7 +721,7 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 			if value, ok := field.TagSettings["COLUMN"]; ok {
 				field.DBName = value
 			} else {
-				field.DBName = ToDBName(fieldStruct.Name)
+				field.DBName = namingStrategy.ColumnName(fieldStruct.Name)
 			}
 
 			modelStruct.StructFields = append(modelStruct.StructFields, field)
@@ -528,13 +729,15 @@ func (scope *Scope) GetModelStruct() *ModelStruct {
 	}
 
 	if len(modelStruct.PrimaryFields) == 0 {
-		if field := getForeignField("id", modelStruct.StructFields); field != nil {
+		if field := getForeignField(namingStrategy.ColumnName("ID"), modelStruct.StructFields); field != nil {
 			field.IsPrimaryKey = true
 			modelStruct.PrimaryFields = append(modelStruct.PrimaryFields, field)
 		}
 	}
 
-	modelStructsMap.Set(reflectType, &modelStruct)
+	modelStruct.parseIndexes()
+
+	modelStructs.Set(reflectType, &modelStruct)
 
 	return &modelStruct
 }
@@ -1,10 +1,11 @@
 package gorm_test
 
 import (
-	"os"
 	"reflect"
 	"sort"
 	"testing"
+
+	"github.com/nkovacs/gorm"
 )
 
 type Blog struct {
@@ -35,7 +36,7 @@ func compareTags(tags []Tag, contents []string) bool {
 }
 
 func TestManyToManyWithMultiPrimaryKeys(t *testing.T) {
-	if dialect := os.Getenv("GORM_DIALECT"); dialect != "" && dialect != "sqlite" {
+	if DB.Dialect().Capabilities().AutoIncrement != gorm.AutoIncrementPrimaryKey {
 		DB.DropTable(&Blog{}, &Tag{})
 		DB.DropTable("blog_tags")
 		DB.CreateTable(&Blog{}, &Tag{})
@@ -119,7 +120,7 @@ func TestManyToManyWithMultiPrimaryKeys(t *testing.T) {
 }
 
 func TestManyToManyWithCustomizedForeignKeys(t *testing.T) {
-	if dialect := os.Getenv("GORM_DIALECT"); dialect != "" && dialect != "sqlite" {
+	if DB.Dialect().Capabilities().AutoIncrement != gorm.AutoIncrementPrimaryKey {
 		DB.DropTable(&Blog{}, &Tag{})
 		DB.DropTable("shared_blog_tags")
 		DB.CreateTable(&Blog{}, &Tag{})
@@ -236,7 +237,7 @@ func TestManyToManyWithCustomizedForeignKeys(t *testing.T) {
 }
 
 func TestManyToManyWithCustomizedForeignKeys2(t *testing.T) {
-	if dialect := os.Getenv("GORM_DIALECT"); dialect != "" && dialect != "sqlite" {
+	if DB.Dialect().Capabilities().AutoIncrement != gorm.AutoIncrementPrimaryKey {
 		DB.DropTable(&Blog{}, &Tag{})
 		DB.DropTable("locale_blog_tags")
 		DB.CreateTable(&Blog{}, &Tag{})
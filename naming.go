@@ -0,0 +1,89 @@
+package gorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+)
+
+// NamingStrategy build names of tables, columns, join tables, indexes, foreign keys and check constraints,
+// DBs could use different conventions with `SetNamingStrategy`
+type NamingStrategy interface {
+	// TableName return table name of model with struct name, it isn't used if model implements `TableName`
+	TableName(structName string) string
+	// ColumnName return column name of field with field name, it isn't used if field has tag `column`
+	ColumnName(fieldName string) string
+	// JoinTableName return table name of many2many join table with name of tag `many2many`
+	JoinTableName(name string) string
+	// IndexName return name of index on table's column, it isn't used if index is named with tag
+	IndexName(table, column string, unique bool) string
+	// ForeignKeyName return name of foreign key on table's columns, reference is like `cities(id)`
+	ForeignKeyName(table, columns, reference string) string
+	// CheckName return name of check constraint on table's column, it isn't used if check is named with tag
+	CheckName(table, column string) string
+}
+
+// DefaultNamingStrategy snake case names, plural table names
+//     db.SetNamingStrategy(gorm.DefaultNamingStrategy{TablePrefix: "t_", Schema: "reporting"})
+//     // type User struct{} => `reporting.t_users`
+type DefaultNamingStrategy struct {
+	// TablePrefix prefix of table names and join table names
+	TablePrefix string
+	// SingularTable use singular table names
+	SingularTable bool
+	// Schema schema of tables and join tables, names are qualified like `schema.table` if it is set
+	Schema string
+}
+
+// TableName snake case struct name, plural if SingularTable isn't set
+func (ns DefaultNamingStrategy) TableName(structName string) string {
+	tableName := ToDBName(structName)
+	if !ns.SingularTable {
+		tableName = inflection.Plural(tableName)
+	}
+	return ns.qualify(ns.TablePrefix + tableName)
+}
+
+// ColumnName snake case field name
+func (DefaultNamingStrategy) ColumnName(fieldName string) string {
+	return ToDBName(fieldName)
+}
+
+// JoinTableName name of tag `many2many` with prefix and schema
+func (ns DefaultNamingStrategy) JoinTableName(name string) string {
+	return ns.qualify(ns.TablePrefix + name)
+}
+
+// IndexName `idx_table_column`, or `uix_table_column` for unique indexes
+func (DefaultNamingStrategy) IndexName(table, column string, unique bool) string {
+	if unique {
+		return fmt.Sprintf("uix_%v_%v", unqualifiedTableName(table), column)
+	}
+	return fmt.Sprintf("idx_%v_%v", unqualifiedTableName(table), column)
+}
+
+// ForeignKeyName `table_columns_reference_foreign`, characters other than letters are replaced with `_`
+func (DefaultNamingStrategy) ForeignKeyName(table, columns, reference string) string {
+	return DefaultForeignKeyNamer{}.BuildForeignKeyName(unqualifiedTableName(table), columns, reference)
+}
+
+// CheckName `chk_table_column`
+func (DefaultNamingStrategy) CheckName(table, column string) string {
+	return fmt.Sprintf("chk_%v_%v", unqualifiedTableName(table), column)
+}
+
+func (ns DefaultNamingStrategy) qualify(tableName string) string {
+	if ns.Schema == "" {
+		return tableName
+	}
+	return ns.Schema + "." + tableName
+}
+
+// unqualifiedTableName table name without schema
+func unqualifiedTableName(tableName string) string {
+	if idx := strings.LastIndex(tableName, "."); idx >= 0 {
+		return tableName[idx+1:]
+	}
+	return tableName
+}
@@ -0,0 +1,242 @@
+package gorm
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefaultPageSize page size used by `Paginate` if not specified
+var DefaultPageSize = 20
+
+// Pagination page request and page metadata of `Paginate`
+type Pagination struct {
+	// Page page number starts from 1, ignored when Cursor is set
+	Page     int
+	PageSize int
+	// Cursor opaque cursor of the page, which is `NextCursor` of previous page
+	Cursor string
+
+	// Total count of records that match conditions
+	Total      int64
+	TotalPages int
+	HasNext    bool
+	// NextCursor cursor of next page, blank if there is no next page or orders couldn't be used as cursor
+	NextCursor string
+}
+
+var orderColumnRegexp = regexp.MustCompile("(?i)^([\\w\\.\"`\\[\\]]+)(?:\\s+(ASC|DESC))?$")
+
+type cursorColumn struct {
+	column string
+	field  *StructField
+	desc   bool
+}
+
+// Paginate count records that match current conditions and find a page of them, orders, limit and offset are not used when counting,
+// limit and offset of the page are decided by pagination, orders by nullable fields couldn't be used as cursor
+//     var pagination = gorm.Pagination{Page: 2, PageSize: 20}
+//     db.Where("age > ?", 18).Order("age DESC").Paginate(&users, &pagination)
+//     // next page with cursor, records are found with `WHERE age < last_age OR (age = last_age AND id > last_id)`
+//     db.Where("age > ?", 18).Order("age DESC").Paginate(&users, &gorm.Pagination{Cursor: pagination.NextCursor})
+func (s *DB) Paginate(out interface{}, pagination *Pagination) *DB {
+	var (
+		c       = s.clone()
+		scope   = c.NewScope(out)
+		results = reflect.Indirect(reflect.ValueOf(out))
+	)
+
+	if results.Kind() != reflect.Slice {
+		c.AddError(errors.New("Paginate requires a pointer to slice"))
+		return c
+	}
+
+	if pagination.PageSize <= 0 {
+		pagination.PageSize = DefaultPageSize
+	}
+	if pagination.Page <= 0 {
+		pagination.Page = 1
+	}
+
+	countDB := c.clone()
+	countDB.search.orders, countDB.search.limit, countDB.search.offset, countDB.search.preload = nil, -1, -1, nil
+	if c.AddError(countDB.Model(out).Count(&pagination.Total).Error) != nil {
+		return c
+	}
+	pagination.TotalPages = int((pagination.Total + int64(pagination.PageSize) - 1) / int64(pagination.PageSize))
+
+	cursorColumns, cursorErr := scope.cursorColumns()
+	query := c.Limit(pagination.PageSize + 1)
+	if cursorErr == nil {
+		query = query.Order(nil, true)
+		for _, column := range cursorColumns {
+			if column.desc {
+				query = query.Order(column.column + " DESC")
+			} else {
+				query = query.Order(column.column)
+			}
+		}
+	}
+
+	if pagination.Cursor != "" {
+		if cursorErr != nil {
+			c.AddError(cursorErr)
+			return c
+		}
+
+		sql, args, err := cursorCondition(cursorColumns, pagination.Cursor)
+		if c.AddError(err) != nil {
+			return c
+		}
+		query = query.Where(sql, args...).Offset(-1)
+	} else {
+		query = query.Offset((pagination.Page - 1) * pagination.PageSize)
+	}
+
+	if c.AddError(query.Find(out).Error) != nil {
+		return c
+	}
+
+	pagination.HasNext, pagination.NextCursor = results.Len() > pagination.PageSize, ""
+	if pagination.HasNext {
+		results.SetLen(pagination.PageSize)
+		if cursorErr == nil {
+			pagination.NextCursor, cursorErr = encodeCursor(cursorColumns, c.NewScope(reflect.Indirect(results.Index(results.Len()-1)).Addr().Interface()))
+			c.AddError(cursorErr)
+		}
+	}
+	c.RowsAffected = int64(results.Len())
+	return c
+}
+
+// cursorColumns columns of current orders used as cursor, primary keys are appended to make it unique
+func (scope *Scope) cursorColumns() (columns []cursorColumn, err error) {
+	var (
+		modelStruct = scope.GetModelStruct()
+		hasColumn   = map[string]bool{}
+	)
+
+	addColumn := func(name string, desc bool) error {
+		dbName := name
+		if idx := strings.LastIndex(dbName, "."); idx >= 0 {
+			dbName = dbName[idx+1:]
+		}
+		dbName = strings.Trim(dbName, "\"`[]")
+
+		for _, field := range modelStruct.StructFields {
+			if field.IsNormal && field.DBName == dbName {
+				if nullableField(field) {
+					return fmt.Errorf("order %v couldn't be used as cursor, as NULL couldn't be compared", name)
+				}
+
+				if !hasColumn[dbName] {
+					hasColumn[dbName] = true
+					columns = append(columns, cursorColumn{column: name, field: field, desc: desc})
+				}
+				return nil
+			}
+		}
+		return fmt.Errorf("order %v couldn't be used as cursor", name)
+	}
+
+	for _, order := range scope.Search.orders {
+		str, ok := order.(string)
+		if !ok {
+			return nil, fmt.Errorf("order %v couldn't be used as cursor", order)
+		}
+
+		for _, part := range strings.Split(str, ",") {
+			matches := orderColumnRegexp.FindStringSubmatch(strings.TrimSpace(part))
+			if matches == nil {
+				return nil, fmt.Errorf("order %v couldn't be used as cursor", part)
+			}
+			if err := addColumn(matches[1], strings.ToUpper(matches[2]) == "DESC"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, field := range modelStruct.PrimaryFields {
+		if err := addColumn(fmt.Sprintf("%v.%v", scope.QuotedTableName(), scope.Quote(field.DBName)), false); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, errors.New("cursor requires orders or primary keys")
+	}
+	return columns, nil
+}
+
+// nullableField check if field could hold NULL, which are pointers and types like `sql.NullString` with a `Valid` field,
+// unless the field is a primary key or tagged with `not null`
+func nullableField(field *StructField) bool {
+	if _, ok := field.TagSettings["NOT NULL"]; ok || field.IsPrimaryKey {
+		return false
+	}
+
+	fieldType := field.Struct.Type
+	if fieldType.Kind() == reflect.Ptr {
+		return true
+	}
+
+	if fieldType.Kind() == reflect.Struct {
+		if valid, ok := fieldType.FieldByName("Valid"); ok && valid.Type.Kind() == reflect.Bool {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeCursor(columns []cursorColumn, scope *Scope) (string, error) {
+	var values []interface{}
+	for _, column := range columns {
+		field, _ := scope.FieldByName(column.field.Name)
+		values = append(values, field.Field.Interface())
+	}
+
+	bytes, err := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(bytes), err
+}
+
+// cursorCondition build condition like `a > ? OR (a = ? AND b > ?)` with values decoded from cursor
+func cursorCondition(columns []cursorColumn, cursor string) (sql string, args []interface{}, err error) {
+	var rawValues []json.RawMessage
+	bytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(bytes, &rawValues)
+	}
+	if err != nil || len(rawValues) != len(columns) {
+		return "", nil, errors.New("invalid cursor")
+	}
+
+	var (
+		values     []interface{}
+		conditions []string
+	)
+
+	for idx, column := range columns {
+		value := reflect.New(column.field.Struct.Type)
+		if err := json.Unmarshal(rawValues[idx], value.Interface()); err != nil {
+			return "", nil, errors.New("invalid cursor")
+		}
+
+		operator := ">"
+		if column.desc {
+			operator = "<"
+		}
+
+		var condition []string
+		for _, previous := range columns[:idx] {
+			condition = append(condition, previous.column+" = ?")
+		}
+		conditions = append(conditions, "("+strings.Join(append(condition, fmt.Sprintf("%v %v ?", column.column, operator)), " AND ")+")")
+		args = append(append(args, values...), value.Elem().Interface())
+		values = append(values, value.Elem().Interface())
+	}
+	return strings.Join(conditions, " OR "), args, nil
+}
@@ -0,0 +1,131 @@
+package gorm_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/nkovacs/gorm"
+)
+
+func TestPaginate(t *testing.T) {
+	for _, age := range []int64{30, 10, 20, 10, 30, 20, 10} {
+		DB.Save(&User{Name: "page_user", Age: age})
+	}
+
+	var (
+		users      []User
+		pagination = gorm.Pagination{Page: 1, PageSize: 3}
+		scopedDB   = DB.Where("name = ?", "page_user").Order("age DESC").Limit(1).Offset(2)
+		offsetIDs  []int64
+	)
+
+	for ; ; pagination.Page++ {
+		if err := scopedDB.Paginate(&users, &pagination).Error; err != nil {
+			t.Fatalf("No error should happen when paginate, but got %+v", err)
+		}
+
+		if pagination.Total != 7 || pagination.TotalPages != 3 {
+			t.Errorf("Orders, limit and offset shouldn't be used when counting, but got %+v", pagination)
+		}
+
+		for _, user := range users {
+			offsetIDs = append(offsetIDs, user.Id)
+		}
+
+		if !pagination.HasNext {
+			break
+		}
+
+		if len(users) != 3 || pagination.NextCursor == "" {
+			t.Errorf("Page %v should have 3 users and next cursor, but got %v, %+v", pagination.Page, len(users), pagination)
+		}
+	}
+
+	if pagination.Page != 3 || len(users) != 1 || len(offsetIDs) != 7 {
+		t.Errorf("Should get 3 pages, but got %v pages, %v", pagination.Page, offsetIDs)
+	}
+
+	var lastAge int64 = 100
+	for _, id := range offsetIDs {
+		var user User
+		DB.First(&user, id)
+		if user.Age > lastAge {
+			t.Errorf("Users should be ordered by age, but got %v", offsetIDs)
+		}
+		lastAge = user.Age
+	}
+
+	var cursorIDs []int64
+	pagination = gorm.Pagination{PageSize: 3}
+	for {
+		if err := scopedDB.Paginate(&users, &pagination).Error; err != nil {
+			t.Fatalf("No error should happen when paginate with cursor, but got %+v", err)
+		}
+
+		for _, user := range users {
+			cursorIDs = append(cursorIDs, user.Id)
+		}
+
+		if pagination.Cursor = pagination.NextCursor; pagination.Cursor == "" {
+			break
+		}
+	}
+
+	if fmt.Sprint(cursorIDs) != fmt.Sprint(offsetIDs) {
+		t.Errorf("Pages found with cursor should be same as pages found with offset, but got %v, %v", cursorIDs, offsetIDs)
+	}
+
+	if err := scopedDB.Paginate(&users, &gorm.Pagination{Cursor: "invalid"}).Error; err == nil {
+		t.Errorf("Should got error with invalid cursor")
+	}
+
+	if err := DB.Order(gorm.Expr("age * 2")).Paginate(&users, &gorm.Pagination{Cursor: "WzFd"}).Error; err == nil {
+		t.Errorf("Should got error when orders couldn't be used as cursor")
+	}
+}
+
+func TestPaginateWithNullOrders(t *testing.T) {
+	type NullableScore struct {
+		ID    int64
+		Score *int64
+	}
+	DB.DropTableIfExists(&NullableScore{})
+	DB.AutoMigrate(&NullableScore{})
+	defer DB.DropTableIfExists(&NullableScore{})
+
+	ten, twenty := int64(10), int64(20)
+	for _, score := range []*int64{nil, &ten, nil, &twenty} {
+		DB.Create(&NullableScore{Score: score})
+	}
+
+	var (
+		records    []NullableScore
+		pagination = gorm.Pagination{PageSize: 1}
+		ids        = map[int64]bool{}
+	)
+	for ; ; pagination.Page++ {
+		if err := DB.Order("score").Paginate(&records, &pagination).Error; err != nil {
+			t.Fatalf("No error should happen when paginate by nullable column, but got %+v", err)
+		}
+
+		if pagination.NextCursor != "" {
+			t.Errorf("Nullable column shouldn't be used as cursor, but got %v", pagination.NextCursor)
+		}
+
+		for _, record := range records {
+			ids[record.ID] = true
+		}
+
+		if !pagination.HasNext {
+			break
+		}
+	}
+
+	if len(ids) != 4 {
+		t.Errorf("Every record should be found with NULL orders, but got %v", ids)
+	}
+
+	if err := DB.Order("score").Paginate(&records, &gorm.Pagination{Cursor: "W251bGwsMV0"}).Error; err == nil {
+		t.Errorf("Should got error when paginate with cursor by nullable column")
+	}
+}
@@ -2,11 +2,15 @@ package gorm_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"encoding/json"
-	"os"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/erikstmartin/go-testdb"
 	"github.com/nkovacs/gorm"
 )
 
@@ -789,7 +793,7 @@ func TestNestedPreload12(t *testing.T) {
 }
 
 func TestManyToManyPreloadWithMultiPrimaryKeys(t *testing.T) {
-	if dialect := os.Getenv("GORM_DIALECT"); dialect == "" || dialect == "sqlite" {
+	if DB.Dialect().Capabilities().AutoIncrement == gorm.AutoIncrementPrimaryKey {
 		return
 	}
 
@@ -1591,6 +1595,400 @@ func TestPrefixedPreloadDuplication(t *testing.T) {
 	}
 }
 
+func TestWildcardPreload(t *testing.T) {
+	user := getPreloadUser("wildcard_preload")
+	DB.Save(user)
+	defer DB.Delete(user)
+
+	var got User
+	if err := DB.Preload("*").First(&got, user.Id).Error; err != nil {
+		t.Fatalf("No error should happen when preload with wildcard, but got %+v", err)
+	}
+	checkUserHasPreloadData(got, t)
+
+	if len(got.Languages) != 2 {
+		t.Errorf("Many to many relations should be preloaded with wildcard, but got %+v", got.Languages)
+	}
+	for _, language := range got.Languages {
+		if len(language.Users) != 0 {
+			t.Errorf("Relations refer to preloaded models shouldn't be preloaded again, but got %+v", language.Users)
+		}
+	}
+
+	var blank User
+	if err := DB.Preload("*", func(db *gorm.DB) *gorm.DB { return db.Where("1 = 0") }).First(&blank, user.Id).Error; err != nil {
+		t.Fatalf("No error should happen when preload with wildcard and scopes, but got %+v", err)
+	}
+	if blank.CreditCard.ID != 0 || blank.Company.Id != 0 || len(blank.Emails) != 0 || len(blank.Languages) != 0 {
+		t.Errorf("Scopes should be applied to all preloaded relations, but got %+v", blank)
+	}
+
+	if err := DB.Preload("*", "name = ?", "wildcard").First(&blank, user.Id).Error; err == nil {
+		t.Errorf("Should got error when preload with wildcard and inline conditions")
+	}
+}
+
+func TestNestedWildcardPreload(t *testing.T) {
+	type (
+		Level1 struct {
+			ID       uint
+			Value    string
+			Level2ID uint
+		}
+		Level2 struct {
+			ID       uint
+			Level1s  []Level1
+			Level3ID uint
+		}
+		Level3 struct {
+			ID     uint
+			Name   string
+			Level2 Level2
+		}
+	)
+	DB.DropTableIfExists(&Level3{}, &Level2{}, &Level1{})
+	if err := DB.AutoMigrate(&Level3{}, &Level2{}, &Level1{}).Error; err != nil {
+		t.Error(err)
+	}
+
+	want := Level3{Level2: Level2{Level1s: []Level1{{Value: "value1"}, {Value: "value2"}}}}
+	if err := DB.Create(&want).Error; err != nil {
+		t.Error(err)
+	}
+
+	for _, preload := range []string{"*", "Level2.*"} {
+		var got Level3
+		if err := DB.Preload(preload).Find(&got).Error; err != nil {
+			t.Error(err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("preload %v: got %s; want %s", preload, toJSONString(got), toJSONString(want))
+		}
+	}
+
+	var got Level3
+	if err := DB.Set("gorm:preload_max_depth", 1).Preload("*").Find(&got).Error; err != nil {
+		t.Error(err)
+	}
+	if got.Level2.ID != want.Level2.ID || len(got.Level2.Level1s) != 0 {
+		t.Errorf("Should only preload one level, but got %s", toJSONString(got))
+	}
+
+	if err := DB.Preload("Unknown.*").Find(&got).Error; err == nil {
+		t.Errorf("Should got error when preload unknown relation with wildcard")
+	}
+}
+
+func TestPreloadWithLimitPerParent(t *testing.T) {
+	if !DB.Dialect().Capabilities().WindowFunctions {
+		t.Skip("Skipping this because limits are applied to the whole preload query without window functions")
+	}
+
+	var users []*User
+	for _, name := range []string{"limit_preload_1", "limit_preload_2"} {
+		user := getPreloadUser(name)
+		user.Emails = append(user.Emails, Email{Email: name + "@example.org"})
+		DB.Save(user)
+		users = append(users, user)
+	}
+	defer DB.Where("name LIKE ?", "limit_preload_%").Delete(&User{})
+
+	latestEmail := func(db *gorm.DB) *gorm.DB { return db.Order("id DESC").Limit(2) }
+	firstLanguage := func(db *gorm.DB) *gorm.DB { return db.Order("name").Limit(1) }
+
+	var got []User
+	if err := DB.Where("name IN (?)", []string{"limit_preload_1", "limit_preload_2"}).Order("id").
+		Preload("Emails", latestEmail).Preload("Languages", firstLanguage).Find(&got).Error; err != nil {
+		t.Fatalf("No error should happen when preload with limit, but got %+v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Should find two users, but got %v", len(got))
+	}
+
+	for idx, user := range got {
+		want := users[idx].Emails
+		if len(user.Emails) != 2 || user.Emails[0].Id != want[2].Id || user.Emails[1].Id != want[1].Id {
+			t.Errorf("Should preload latest two emails of %v, but got %s", user.Name, toJSONString(user.Emails))
+		}
+
+		if len(user.Languages) != 1 || user.Languages[0].Name != users[idx].Languages[0].Name {
+			t.Errorf("Should preload first language of %v, but got %s", user.Name, toJSONString(user.Languages))
+		}
+	}
+
+	var offsetUser User
+	DB.Preload("Emails", func(db *gorm.DB) *gorm.DB { return db.Order("id DESC").Limit(5).Offset(2) }).First(&offsetUser, users[0].Id)
+	if len(offsetUser.Emails) != 1 || offsetUser.Emails[0].Id != users[0].Emails[0].Id {
+		t.Errorf("Should skip latest two emails, but got %s", toJSONString(offsetUser.Emails))
+	}
+
+	if DB.Dialect().Capabilities().LateralJoin {
+		var lateralUsers []User
+		if err := DB.Set("gorm:preload_lateral", true).Where("name IN (?)", []string{"limit_preload_1", "limit_preload_2"}).Order("id").
+			Preload("Emails", latestEmail).Find(&lateralUsers).Error; err != nil {
+			t.Fatalf("No error should happen when preload with LATERAL join, but got %+v", err)
+		}
+
+		for idx, user := range lateralUsers {
+			if want := users[idx].Emails; len(user.Emails) != 2 || user.Emails[0].Id != want[2].Id || user.Emails[1].Id != want[1].Id {
+				t.Errorf("Should preload latest two emails of %v with LATERAL join, but got %s", user.Name, toJSONString(user.Emails))
+			}
+		}
+	}
+}
+
+func TestPreloadWithLimitWithoutDialectSupport(t *testing.T) {
+	user := getPreloadUser("limit_preload_unsupported")
+	user.Emails = append(user.Emails, Email{Email: "limit_preload_unsupported@example.org"})
+	DB.Save(user)
+	defer DB.Delete(user)
+
+	latestEmail := func(db *gorm.DB) *gorm.DB { return db.Order("id DESC").Limit(1) }
+
+	if !DB.Dialect().Capabilities().LateralJoin {
+		var got User
+		if err := DB.Set("gorm:preload_lateral", true).Preload("Emails", latestEmail).First(&got, user.Id).Error; err == nil {
+			t.Errorf("Should got error when preload with LATERAL join that isn't supported")
+		}
+	}
+
+	// sqlite before 3.25.0 and mysql before 8.0 don't support window functions
+	oldDB, err := gorm.OpenWith(DB.Dialect().GetName(), DB.DB(), gorm.WithDialectConfig(gorm.DialectConfig{ServerVersion: "3.24.0"}))
+	if err != nil {
+		t.Fatalf("No error should happen when open with existing connection, but got %+v", err)
+	}
+
+	if !oldDB.Dialect().Capabilities().WindowFunctions {
+		var got User
+		if err := oldDB.Preload("Emails", latestEmail).First(&got, user.Id).Error; err != nil {
+			t.Fatalf("No error should happen when preload with limit without window functions, but got %+v", err)
+		}
+
+		if len(got.Emails) != 1 || got.Emails[0].Id != user.Emails[2].Id {
+			t.Errorf("Should limit the whole preload query without window functions, but got %s", toJSONString(got.Emails))
+		}
+	}
+}
+
+type LateralComment struct {
+	ID            uint
+	LateralPostID uint
+}
+
+type LateralPost struct {
+	ID       uint
+	Comments []LateralComment
+}
+
+func (LateralPost) TableName() string {
+	return "lateral posts"
+}
+
+func TestPreloadWithLateralQuotedTable(t *testing.T) {
+	defer testdb.Reset()
+
+	var queries []string
+	testdb.SetQueryWithArgsFunc(func(query string, args []driver.Value) (driver.Rows, error) {
+		queries = append(queries, query)
+		if strings.Contains(query, "LATERAL") {
+			return testdb.RowsFromCSVString([]string{"id", "lateral_post_id"}, "1,1"), nil
+		}
+		return testdb.RowsFromCSVString([]string{"id"}, "1"), nil
+	})
+
+	sqlDB, _ := sql.Open("testdb", "")
+	db, err := gorm.Open("postgres", sqlDB)
+	if err != nil {
+		t.Fatalf("No error should happen when open postgres with testdb, but got %+v", err)
+	}
+
+	var posts []LateralPost
+	if err := db.Set("gorm:preload_lateral", true).Preload("Comments", func(db *gorm.DB) *gorm.DB { return db.Limit(2) }).Find(&posts).Error; err != nil {
+		t.Fatalf("No error should happen when preload with LATERAL join, but got %+v", err)
+	}
+
+	expected := `FROM "lateral posts" WHERE "lateral posts"."id" IN ($1)) gorm_parents CROSS JOIN LATERAL`
+	if len(queries) != 2 || !strings.Contains(queries[1], expected) {
+		t.Errorf("Parent columns of LATERAL join should be quoted, but got %v", queries)
+	}
+
+	if len(posts) != 1 || len(posts[0].Comments) != 1 {
+		t.Errorf("Should preload comments with LATERAL join, but got %s", toJSONString(posts))
+	}
+}
+
+func TestPreloadInChunks(t *testing.T) {
+	var names []string
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("chunk_preload_%v", i)
+		DB.Save(getPreloadUser(name))
+		names = append(names, name)
+	}
+	defer DB.Where("name IN (?)", names).Delete(&User{})
+
+	for _, parallel := range []bool{false, true} {
+		var users []User
+		err := DB.Set("gorm:preload_chunk_size", 2).Set("gorm:preload_parallel", parallel).Where("name IN (?)", names).
+			Preload("Emails").Preload("CreditCard").Preload("Company").Preload("BillingAddress").Preload("ShippingAddress").Preload("Languages").Find(&users).Error
+		if err != nil {
+			t.Fatalf("No error should happen when preload in chunks, but got %+v", err)
+		}
+
+		if len(users) != 5 {
+			t.Fatalf("Should find five users, but got %v", len(users))
+		}
+
+		for _, user := range users {
+			checkUserHasPreloadData(user, t)
+			if len(user.Languages) != 2 {
+				t.Errorf("Many to many relations should be preloaded in chunks, but got %+v", user.Languages)
+			}
+		}
+	}
+
+	// chunks are preloaded one by one in transaction, which has only one connection
+	tx := DB.Begin()
+	defer tx.Rollback()
+
+	var users []User
+	if err := tx.Set("gorm:preload_chunk_size", 2).Set("gorm:preload_parallel", true).Where("name IN (?)", names).
+		Preload("Emails").Preload("Languages").Find(&users).Error; err != nil {
+		t.Fatalf("No error should happen when preload in chunks in transaction, but got %+v", err)
+	}
+
+	for _, user := range users {
+		if len(user.Emails) != 2 || len(user.Languages) != 2 {
+			t.Errorf("Associations should be preloaded in chunks in transaction, but got %+v, %+v", user.Emails, user.Languages)
+		}
+	}
+}
+
+func TestPreloadNestedInParallelChunks(t *testing.T) {
+	type (
+		ChunkLevel3 struct {
+			ID            uint
+			Value         string
+			ChunkLevel2ID uint
+		}
+		ChunkLevel2 struct {
+			ID            uint
+			ChunkLevel1ID uint
+			Level3s       []ChunkLevel3
+		}
+		ChunkLevel1 struct {
+			ID      uint
+			Level2s []ChunkLevel2
+		}
+	)
+
+	DB.DropTableIfExists(&ChunkLevel3{}, &ChunkLevel2{}, &ChunkLevel1{})
+	if err := DB.AutoMigrate(&ChunkLevel3{}, &ChunkLevel2{}, &ChunkLevel1{}).Error; err != nil {
+		t.Fatalf("Failed to migrate, got %v", err)
+	}
+	defer DB.DropTableIfExists(&ChunkLevel3{}, &ChunkLevel2{}, &ChunkLevel1{})
+
+	for i := 0; i < 7; i++ {
+		level1 := ChunkLevel1{}
+		for j := 0; j < 3; j++ {
+			level1.Level2s = append(level1.Level2s, ChunkLevel2{Level3s: []ChunkLevel3{{Value: fmt.Sprintf("%v_%v_a", i, j)}, {Value: fmt.Sprintf("%v_%v_b", i, j)}}})
+		}
+		if err := DB.Create(&level1).Error; err != nil {
+			t.Fatalf("Failed to create nested records, got %v", err)
+		}
+	}
+
+	// run with -race, chunks of every level are preloaded concurrently into the shared parents
+	var got []ChunkLevel1
+	if err := DB.Set("gorm:preload_chunk_size", 2).Set("gorm:preload_parallel", true).Order("id").
+		Preload("Level2s", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).Preload("Level2s.Level3s", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Find(&got).Error; err != nil {
+		t.Fatalf("No error should happen when preload nested associations in parallel chunks, but got %+v", err)
+	}
+
+	if len(got) != 7 {
+		t.Fatalf("Should find 7 records, but got %v", len(got))
+	}
+
+	for i, level1 := range got {
+		if len(level1.Level2s) != 3 {
+			t.Fatalf("Should preload 3 children for %v, but got %v", level1.ID, toJSONString(level1.Level2s))
+		}
+
+		for j, level2 := range level1.Level2s {
+			if len(level2.Level3s) != 2 || level2.Level3s[0].Value != fmt.Sprintf("%v_%v_a", i, j) || level2.Level3s[1].Value != fmt.Sprintf("%v_%v_b", i, j) {
+				t.Errorf("Should preload nested children in parallel chunks for %v, but got %v", level2.ID, toJSONString(level2.Level3s))
+			}
+		}
+	}
+}
+
+func TestPreloadCount(t *testing.T) {
+	type (
+		CountComment struct {
+			ID          uint
+			CountPostID uint
+			Approved    bool
+			DeletedAt   *time.Time
+		}
+		CountTag struct {
+			ID   uint
+			Name string
+		}
+		CountPost struct {
+			ID            uint
+			Comments      []CountComment
+			CommentsCount int        `gorm:"count:Comments"`
+			Tags          []CountTag `gorm:"many2many:count_post_tags"`
+			TagsCount     uint       `gorm:"count:Tags"`
+			InvalidCount  int        `gorm:"count:Unknown"`
+		}
+	)
+
+	DB.DropTableIfExists(&CountPost{}, &CountComment{}, &CountTag{}, "count_post_tags")
+	if err := DB.AutoMigrate(&CountPost{}, &CountComment{}, &CountTag{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if DB.Dialect().HasColumn("count_posts", "comments_count") {
+		t.Errorf("Count fields shouldn't be migrated as columns")
+	}
+
+	posts := []CountPost{
+		{Comments: []CountComment{{Approved: true}, {Approved: true}, {}}, Tags: []CountTag{{Name: "go"}, {Name: "sql"}}},
+		{Comments: []CountComment{{}}},
+		{},
+	}
+	for idx := range posts {
+		DB.Save(&posts[idx])
+	}
+	DB.Delete(&posts[0].Comments[0])
+
+	var got []CountPost
+	if err := DB.PreloadCount("Comments").PreloadCount("Tags").Order("id").Find(&got).Error; err != nil {
+		t.Fatalf("No error should happen when preload count, but got %+v", err)
+	}
+
+	if len(got) != 3 || got[0].CommentsCount != 2 || got[1].CommentsCount != 1 || got[2].CommentsCount != 0 {
+		t.Errorf("Should count comments of every post without deleted ones, but got %s", toJSONString(got))
+	}
+
+	if got[0].TagsCount != 2 || got[1].TagsCount != 0 || len(got[0].Tags) != 0 {
+		t.Errorf("Should only count many to many associations, but got %s", toJSONString(got))
+	}
+
+	var post CountPost
+	DB.Set("gorm:preload_chunk_size", 1).PreloadCount("Comments", "approved = ?", true).First(&post, posts[0].ID)
+	if post.CommentsCount != 1 {
+		t.Errorf("Should count comments with conditions, but got %v", post.CommentsCount)
+	}
+
+	if err := DB.PreloadCount("Unknown").Find(&got).Error; err == nil {
+		t.Errorf("Should got error when preload count of unknown relation")
+	}
+}
+
 func toJSONString(v interface{}) []byte {
 	r, _ := json.MarshalIndent(v, "", "  ")
 	return r
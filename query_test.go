@@ -1,8 +1,10 @@
 package gorm_test
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/jinzhu/now"
 	"github.com/nkovacs/gorm"
@@ -386,6 +388,105 @@ func TestOr(t *testing.T) {
 	}
 }
 
+func TestSearchWithExpr(t *testing.T) {
+	user1 := User{Name: "ExprUser1", Age: 1}
+	user2 := User{Name: "ExprUser2", Age: 10}
+	user3 := User{Name: "ExprUser3", Age: 20}
+	DB.Save(&user1).Save(&user2).Save(&user3)
+
+	var users []User
+	DB.Where(gorm.Expr("name IN (?)", []string{user1.Name, user2.Name, user3.Name})).Where(gorm.Expr("age > ?", 5)).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Should find 2 users with expr conditions, but got %v", len(users))
+	}
+
+	users = nil
+	DB.Where(gorm.Expr("name = ?", user1.Name)).Or(gorm.Expr("name = ?", user3.Name)).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Should find 2 users with or expr conditions, but got %v", len(users))
+	}
+
+	users = nil
+	DB.Where("name LIKE ?", "ExprUser%").Not(gorm.Expr("age BETWEEN ? AND ?", 5, 30)).Find(&users)
+	if len(users) != 1 || users[0].Name != user1.Name {
+		t.Errorf("Should find user1 with not expr conditions, but got %+v", users)
+	}
+}
+
+func TestFindInBatches(t *testing.T) {
+	for i := 0; i < 7; i++ {
+		DB.Save(&User{Name: "batch_user", Age: int64(i)})
+	}
+
+	var (
+		users   []User
+		batches []int
+		ages    []int64
+	)
+
+	result := DB.Where("name = ?", "batch_user").FindInBatches(&users, 3, func(tx *gorm.DB, batch int) error {
+		batches = append(batches, len(users))
+		for _, user := range users {
+			ages = append(ages, user.Age)
+			// update rows that match the conditions, shouldn't affect next batches
+			tx.Model(&user).Update("name", "batch_user_updated")
+		}
+		return nil
+	})
+
+	if result.Error != nil || result.RowsAffected != 7 || fmt.Sprint(batches) != "[3 3 1]" || fmt.Sprint(ages) != "[0 1 2 3 4 5 6]" {
+		t.Errorf("Should find users in batches, but got %v, %v, %v, %+v", batches, ages, result.RowsAffected, result.Error)
+	}
+
+	var count int
+	DB.Model(&User{}).Where("name = ?", "batch_user_updated").Count(&count)
+	if count != 7 {
+		t.Errorf("Should update users in batches, but got %v", count)
+	}
+
+	errStop := errors.New("stop")
+	batches = nil
+	result = DB.Where("name = ?", "batch_user_updated").FindInBatches(&users, 3, func(tx *gorm.DB, batch int) error {
+		batches = append(batches, batch)
+		return errStop
+	})
+
+	if result.Error != errStop || fmt.Sprint(batches) != "[1]" {
+		t.Errorf("Should stop when got error, but got %v, %+v", batches, result.Error)
+	}
+}
+
+type BatchItem struct {
+	Shop  string `gorm:"primary_key"`
+	Code  string `gorm:"primary_key"`
+	Stock int
+}
+
+func TestFindInBatchesWithCompositePrimaryKeys(t *testing.T) {
+	DB.DropTableIfExists(&BatchItem{})
+	DB.AutoMigrate(&BatchItem{})
+
+	for _, shop := range []string{"b", "a"} {
+		for _, code := range []string{"3", "2", "1"} {
+			DB.Create(&BatchItem{Shop: shop, Code: code})
+		}
+	}
+
+	var (
+		items []*BatchItem
+		keys  []string
+	)
+
+	if err := DB.FindInBatches(&items, 2, func(tx *gorm.DB, batch int) error {
+		for _, item := range items {
+			keys = append(keys, fmt.Sprintf("%v%v", item.Shop, item.Code))
+		}
+		return nil
+	}).Error; err != nil || strings.Join(keys, ",") != "a1,a2,a3,b1,b2,b3" {
+		t.Errorf("Should find items in batches ordered by composite primary keys, but got %v, %+v", keys, err)
+	}
+}
+
 func TestCount(t *testing.T) {
 	user1 := User{Name: "CountUser1", Age: 1}
 	user2 := User{Name: "CountUser2", Age: 10}
@@ -642,3 +743,41 @@ func TestSelectWithArrayInput(t *testing.T) {
 		t.Errorf("Should have selected both age and name")
 	}
 }
+
+func TestWhereHas(t *testing.T) {
+	user1 := getPreparedUser("where_has_1", "where_has")
+	user2 := getPreparedUser("where_has_2", "where_has")
+	user2.Emails, user2.Languages = nil, nil
+	DB.Save(user1).Save(user2)
+	defer DB.Where("name LIKE ?", "where_has_%").Delete(&User{})
+
+	var names []string
+	DB.Model(&User{}).Where("name LIKE ?", "where_has_%").WhereHas("Emails").Pluck("name", &names)
+	if len(names) != 1 || names[0] != "where_has_1" {
+		t.Errorf("Should find users that have emails, but got %v", names)
+	}
+
+	names = nil
+	DB.Model(&User{}).Where("name LIKE ?", "where_has_%").WhereHas("Languages", "name = ?", "lang_2_where_has_1").Pluck("name", &names)
+	if len(names) != 1 || names[0] != "where_has_1" {
+		t.Errorf("Should find users that have many to many associations with conditions, but got %v", names)
+	}
+
+	names = nil
+	DB.Model(&User{}).Where("name LIKE ?", "where_has_%").WhereHas("Company", func(db *gorm.DB) *gorm.DB {
+		return db.Where("name = ?", "where_has")
+	}).WhereHas("CreditCard").Order("name").Pluck("name", &names)
+	if len(names) != 2 {
+		t.Errorf("Should find users that have belongs to and has one associations, but got %v", names)
+	}
+
+	names = nil
+	DB.Model(&User{}).Where("name LIKE ?", "where_has_%").WhereHas("Emails", "email = ?", "unknown").Pluck("name", &names)
+	if len(names) != 0 {
+		t.Errorf("Should find no user when no associations match conditions, but got %v", names)
+	}
+
+	if err := DB.WhereHas("Unknown").Find(&[]User{}).Error; err == nil {
+		t.Errorf("Should got error when relation doesn't exist")
+	}
+}
@@ -0,0 +1,378 @@
+package gorm
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type scanMode int
+
+const (
+	scanIgnore   scanMode = iota // column doesn't match any field
+	scanPointer                  // pointer field, scan into it directly, NULL set it to nil
+	scanScanner                  // field implements sql.Scanner
+	scanValue                    // basic kinds, []byte, time.Time, converted without allocation
+	scanFallback                 // other types, scan into a temporary pointer
+)
+
+var (
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// scanPlan columns of result set mapped to struct fields, built once for a model and column list
+type scanPlan struct {
+	columns []scanColumn
+}
+
+type scanColumn struct {
+	mode    scanMode
+	field   *StructField
+	indexes [][]int
+	// joined column of relation loaded with `Joins`, only set if not NULL, so relation pointers are kept nil if nothing joined
+	joined bool
+}
+
+type scanPlanKey struct {
+	modelStruct *ModelStruct
+	columns     string
+}
+
+type safeScanPlansMap struct {
+	m map[scanPlanKey]*scanPlan
+	l *sync.RWMutex
+}
+
+func (s *safeScanPlansMap) Set(key scanPlanKey, value *scanPlan) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.m[key] = value
+}
+
+func (s *safeScanPlansMap) Get(key scanPlanKey) *scanPlan {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	return s.m[key]
+}
+
+var scanPlansMap = &safeScanPlansMap{l: new(sync.RWMutex), m: map[scanPlanKey]*scanPlan{}}
+
+// getScanPlan get cached scan plan for scope's model and columns, columns are matched with fields in the same way as `Scope.scan`
+func getScanPlan(scope *Scope, columns []string) *scanPlan {
+	modelStruct := scope.GetModelStruct()
+	key := scanPlanKey{modelStruct: modelStruct, columns: strings.Join(columns, "\x00")}
+	if plan := scanPlansMap.Get(key); plan != nil {
+		return plan
+	}
+
+	var (
+		plan        = &scanPlan{columns: make([]scanColumn, len(columns))}
+		lastMatched = map[string]int{}
+	)
+
+	for index, column := range columns {
+		start := 0
+		if idx, ok := lastMatched[column]; ok {
+			start = idx + 1
+		}
+
+		var matched *StructField
+		for fieldIndex := start; fieldIndex < len(modelStruct.StructFields); fieldIndex++ {
+			if field := modelStruct.StructFields[fieldIndex]; field.DBName == column {
+				matched = field
+				lastMatched[column] = fieldIndex
+				if field.IsNormal {
+					break
+				}
+			}
+		}
+
+		if matched != nil {
+			plan.columns[index] = newScanColumn(modelStruct.ModelType, matched)
+		} else if column, ok := newJoinedScanColumn(scope, modelStruct, column); ok {
+			plan.columns[index] = column
+		}
+	}
+
+	scanPlansMap.Set(key, plan)
+	return plan
+}
+
+func newScanColumn(modelType reflect.Type, field *StructField) scanColumn {
+	column := scanColumn{field: field}
+
+	// embedded fields are found with names, pointers between them are allocated when scanning
+	structType := modelType
+	for _, name := range field.Names {
+		for structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+
+		structField, _ := structType.FieldByName(name)
+		column.indexes = append(column.indexes, structField.Index)
+		structType = structField.Type
+	}
+
+	switch fieldType := field.Struct.Type; {
+	case fieldType.Kind() == reflect.Ptr:
+		column.mode = scanPointer
+	case reflect.PtrTo(fieldType).Implements(scannerType):
+		column.mode = scanScanner
+	case fieldType == timeType || (fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8):
+		column.mode = scanValue
+	default:
+		switch fieldType.Kind() {
+		case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			column.mode = scanValue
+		default:
+			column.mode = scanFallback
+		}
+	}
+	return column
+}
+
+// newJoinedScanColumn map column like `credit_card__number` to field of has one or belongs to relation
+func newJoinedScanColumn(scope *Scope, modelStruct *ModelStruct, column string) (scanColumn, bool) {
+	idx := strings.Index(column, "__")
+	if idx <= 0 {
+		return scanColumn{}, false
+	}
+
+	for _, field := range modelStruct.StructFields {
+		if field.Relationship == nil || (field.Relationship.Kind != "has_one" && field.Relationship.Kind != "belongs_to") ||
+			scope.namingStrategy().ColumnName(field.Name) != column[:idx] {
+			continue
+		}
+
+		relationType := indirectType(field.Struct.Type)
+		for _, relationField := range scope.relationModelStruct(field).StructFields {
+			if relationField.IsNormal && scope.joinedColumnName(field, relationField) == column {
+				parent := newScanColumn(modelStruct.ModelType, field)
+				joined := newScanColumn(relationType, relationField)
+				joined.indexes = append(parent.indexes, joined.indexes...)
+				joined.mode, joined.joined = scanFallback, true
+				return joined, true
+			}
+		}
+	}
+	return scanColumn{}, false
+}
+
+// rowScanner scan rows of a result set with scan plan, destinations are reused between rows
+type rowScanner struct {
+	plan           *scanPlan
+	values         []interface{}
+	scanners       []fieldScanner
+	holders        []reflect.Value
+	fallbackFields []reflect.Value
+	ignored        sql.RawBytes
+}
+
+func (plan *scanPlan) newScanner() *rowScanner {
+	scanner := &rowScanner{
+		plan:           plan,
+		values:         make([]interface{}, len(plan.columns)),
+		scanners:       make([]fieldScanner, len(plan.columns)),
+		holders:        make([]reflect.Value, len(plan.columns)),
+		fallbackFields: make([]reflect.Value, len(plan.columns)),
+	}
+
+	for index, column := range plan.columns {
+		switch column.mode {
+		case scanIgnore:
+			scanner.values[index] = &scanner.ignored
+		case scanScanner, scanValue:
+			scanner.values[index] = &scanner.scanners[index]
+		case scanFallback:
+			scanner.holders[index] = reflect.New(reflect.PtrTo(column.field.Struct.Type))
+			scanner.values[index] = scanner.holders[index].Interface()
+		}
+	}
+	return scanner
+}
+
+// scan scan current row into struct value, NULL values don't change non-pointer fields
+func (scanner *rowScanner) scan(rows *sql.Rows, value reflect.Value) error {
+	for index, column := range scanner.plan.columns {
+		if column.mode == scanIgnore || column.joined {
+			continue
+		}
+
+		field := fieldByIndexes(value, column.indexes)
+		switch column.mode {
+		case scanPointer:
+			scanner.values[index] = field.Addr().Interface()
+		case scanScanner, scanValue:
+			scanner.scanners[index] = fieldScanner{mode: column.mode, field: field}
+		case scanFallback:
+			scanner.fallbackFields[index] = field
+		}
+	}
+
+	if err := rows.Scan(scanner.values...); err != nil {
+		return err
+	}
+
+	for index, holder := range scanner.holders {
+		if holder.IsValid() {
+			if v := holder.Elem(); !v.IsNil() {
+				if column := scanner.plan.columns[index]; column.joined {
+					fieldByIndexes(value, column.indexes).Set(v.Elem())
+				} else {
+					scanner.fallbackFields[index].Set(v.Elem())
+				}
+				v.Set(reflect.Zero(v.Type()))
+			}
+		}
+	}
+	return nil
+}
+
+// fieldByIndexes find field with index segments, nil pointers between them are allocated
+func fieldByIndexes(value reflect.Value, indexes [][]int) reflect.Value {
+	field := value
+	for _, fieldIndex := range indexes {
+		for field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		field = field.FieldByIndex(fieldIndex)
+	}
+	return field
+}
+
+// fieldScanner implements sql.Scanner, convert driver values into field
+type fieldScanner struct {
+	mode  scanMode
+	field reflect.Value
+}
+
+func (scanner *fieldScanner) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+
+	field := scanner.field
+	if scanner.mode == scanScanner {
+		return field.Addr().Interface().(sql.Scanner).Scan(src)
+	}
+
+	if field.Type() == timeType {
+		if t, ok := src.(time.Time); ok {
+			field.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type %v", src, field.Type())
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case string:
+			field.SetString(v)
+		case []byte:
+			field.SetString(string(v))
+		default:
+			field.SetString(scanString(src))
+		}
+	case reflect.Slice:
+		switch v := src.(type) {
+		case []byte:
+			field.SetBytes(append([]byte(nil), v...))
+		case string:
+			field.SetBytes([]byte(v))
+		default:
+			field.SetBytes([]byte(scanString(src)))
+		}
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			field.SetBool(v)
+		case int64:
+			if v != 0 && v != 1 {
+				return fmt.Errorf("converting driver.Value type %T (%v) to a %v: invalid syntax", src, v, field.Kind())
+			}
+			field.SetBool(v == 1)
+		default:
+			b, err := strconv.ParseBool(scanString(src))
+			if err != nil {
+				return scanConvertError(src, field, err)
+			}
+			field.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v, ok := src.(int64); ok && !field.OverflowInt(v) {
+			field.SetInt(v)
+			return nil
+		}
+
+		i, err := strconv.ParseInt(scanString(src), 10, field.Type().Bits())
+		if err != nil {
+			return scanConvertError(src, field, err)
+		}
+		field.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v, ok := src.(int64); ok && v >= 0 && !field.OverflowUint(uint64(v)) {
+			field.SetUint(uint64(v))
+			return nil
+		}
+
+		u, err := strconv.ParseUint(scanString(src), 10, field.Type().Bits())
+		if err != nil {
+			return scanConvertError(src, field, err)
+		}
+		field.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		switch v := src.(type) {
+		case float64:
+			field.SetFloat(v)
+		case int64:
+			field.SetFloat(float64(v))
+		default:
+			f, err := strconv.ParseFloat(scanString(src), field.Type().Bits())
+			if err != nil {
+				return scanConvertError(src, field, err)
+			}
+			field.SetFloat(f)
+		}
+	default:
+		return errors.New("unsupported scan destination " + field.Type().String())
+	}
+	return nil
+}
+
+// scanString format driver value in the same way as database/sql
+func scanString(src interface{}) string {
+	switch v := src.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%v", src)
+}
+
+func scanConvertError(src interface{}, field reflect.Value, err error) error {
+	if numErr, ok := err.(*strconv.NumError); ok {
+		err = numErr.Err
+	}
+	return fmt.Errorf("converting driver.Value type %T (%q) to a %v: %v", src, scanString(src), field.Kind(), err)
+}
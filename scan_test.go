@@ -0,0 +1,101 @@
+package gorm_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+type ScanRecord struct {
+	ID        int64
+	Name      string
+	Age       int
+	Score     float64
+	Active    bool
+	Nickname  *string
+	Note      sql.NullString
+	Data      []byte
+	CreatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func prepareScanRecords(tb testing.TB, count int) {
+	DB.DropTableIfExists(&ScanRecord{})
+	DB.AutoMigrate(&ScanRecord{})
+
+	tx := DB.Begin()
+	for i := 0; i < count; i++ {
+		record := ScanRecord{Name: "scan", Age: i, Score: float64(i) / 2, Active: i%2 == 0, Data: []byte("data")}
+		if i%3 == 0 {
+			nickname := "nick"
+			record.Nickname = &nickname
+			record.Note = sql.NullString{String: "note", Valid: true}
+		}
+		if err := tx.Create(&record).Error; err != nil {
+			tx.Rollback()
+			tb.Fatalf("No error should happen when create scan records, but got %+v", err)
+		}
+	}
+	tx.Commit()
+}
+
+func TestScanRecords(t *testing.T) {
+	prepareScanRecords(t, 6)
+
+	var records []ScanRecord
+	if err := DB.Order("id").Find(&records).Error; err != nil || len(records) != 6 {
+		t.Fatalf("Should find scan records, but got %v, %+v", len(records), err)
+	}
+
+	for i, record := range records {
+		if record.Age != i || record.Score != float64(i)/2 || record.Active != (i%2 == 0) || string(record.Data) != "data" || record.CreatedAt.IsZero() {
+			t.Errorf("Scanned record %v is not correct, got %+v", i, record)
+		}
+
+		if hasNickname := i%3 == 0; (record.Nickname != nil) != hasNickname || record.Note.Valid != hasNickname {
+			t.Errorf("Scanned record %v should handle NULL values, got %+v", i, record)
+		}
+	}
+
+	var pointers []*ScanRecord
+	if err := DB.Select("id, name, name, age").Order("id").Find(&pointers).Error; err != nil || len(pointers) != 6 || pointers[5].Age != 5 || pointers[5].Name != "scan" {
+		t.Errorf("Should find scan records with selected columns, but got %+v, %+v", pointers, err)
+	}
+
+	record := ScanRecord{Name: "kept", Age: 100}
+	if err := DB.Table("scan_records").Select("id, NULL AS name").Where("age = ?", 1).Scan(&record).Error; err != nil || record.Name != "kept" || record.Age != 100 || record.ID == 0 {
+		t.Errorf("NULL values should not overwrite fields, but got %+v, %+v", record, err)
+	}
+}
+
+func BenchmarkFind10kRows(b *testing.B) {
+	prepareScanRecords(b, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var records []ScanRecord
+		if err := DB.Find(&records).Error; err != nil || len(records) != 10000 {
+			b.Fatalf("Should find 10000 records, but got %v, %+v", len(records), err)
+		}
+	}
+}
+
+func BenchmarkScanRows10kRows(b *testing.B) {
+	prepareScanRecords(b, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		rows, err := DB.Model(&ScanRecord{}).Rows()
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for rows.Next() {
+			var record ScanRecord
+			DB.ScanRows(rows, &record)
+		}
+		rows.Close()
+	}
+}
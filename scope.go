@@ -25,6 +25,8 @@ type Scope struct {
 	skipLeft        bool
 	fields          *[]*Field
 	selectAttrs     *[]string
+	// skipBindVar use `?` as placeholder, used when SQL is built as part of another query
+	skipBindVar bool
 }
 
 // IndirectValue return scope's reflect value's indirect value
@@ -46,6 +48,14 @@ func (scope *Scope) DB() *DB {
 	return scope.db
 }
 
+// namingStrategy return naming strategy of scope's DB
+func (scope *Scope) namingStrategy() NamingStrategy {
+	if scope.db == nil {
+		return DefaultNamingStrategy{}
+	}
+	return scope.db.NamingStrategy()
+}
+
 // NewDB create a new DB without search information
 func (scope *Scope) NewDB() *DB {
 	if scope.db != nil {
@@ -59,6 +69,11 @@ func (scope *Scope) NewDB() *DB {
 
 // SQLDB return *sql.DB
 func (scope *Scope) SQLDB() sqlCommon {
+	if ctx := scope.db.getContext(); ctx != nil {
+		if db, ok := scope.db.db.(sqlContextCommon); ok {
+			return contextDB{db: db, ctx: ctx}
+		}
+	}
 	return scope.db.db
 }
 
@@ -132,7 +147,7 @@ func (scope *Scope) Fields() []*Field {
 // FieldByName find `gorm.Field` with field name or db name
 func (scope *Scope) FieldByName(name string) (field *Field, ok bool) {
 	var (
-		dbName           = ToDBName(name)
+		dbName           = scope.namingStrategy().ColumnName(name)
 		mostMatchedField *Field
 	)
 
@@ -215,7 +230,7 @@ func (scope *Scope) SetColumn(column interface{}, value interface{}) error {
 		return field.Set(value)
 	} else if name, ok := column.(string); ok {
 		var (
-			dbName           = ToDBName(name)
+			dbName           = scope.namingStrategy().ColumnName(name)
 			mostMatchedField *Field
 		)
 		for _, field := range scope.Fields() {
@@ -253,7 +268,7 @@ func (scope *Scope) CallMethod(methodName string) {
 
 // AddToVars add value as sql's vars, used to prevent SQL injection
 func (scope *Scope) AddToVars(value interface{}) string {
-	if expr, ok := value.(*expr); ok {
+	if expr, ok := value.(*SQLExpr); ok {
 		exp := expr.expr
 		for _, arg := range expr.args {
 			exp = strings.Replace(exp, "?", scope.AddToVars(arg), 1)
@@ -261,7 +276,14 @@ func (scope *Scope) AddToVars(value interface{}) string {
 		return exp
 	}
 
+	if column, ok := value.(Column); ok {
+		return scope.quoteColumn(column)
+	}
+
 	scope.SQLVars = append(scope.SQLVars, value)
+	if scope.skipBindVar {
+		return "?"
+	}
 	return scope.Dialect().BindVar(len(scope.SQLVars))
 }
 
@@ -298,6 +320,10 @@ type dbTabler interface {
 	TableName(*DB) string
 }
 
+type tableCommenter interface {
+	TableComment() string
+}
+
 // TableName return table name
 func (scope *Scope) TableName() string {
 	if scope.Search != nil && len(scope.Search.tableName) > 0 {
@@ -384,8 +410,8 @@ func (scope *Scope) InstanceGet(name string) (interface{}, bool) {
 
 // Begin start a transaction
 func (scope *Scope) Begin() *Scope {
-	if db, ok := scope.SQLDB().(sqlDb); ok {
-		if tx, err := db.Begin(); err == nil {
+	if db, ok := scope.db.db.(sqlDb); ok {
+		if tx, err := beginTx(db, scope.db.getContext()); err == nil {
 			scope.db.db = interface{}(tx).(sqlCommon)
 			scope.InstanceSet("gorm:started_transaction", true)
 		}
@@ -444,6 +470,19 @@ func (scope *Scope) callMethod(methodName string, reflectValue reflect.Value) {
 
 var columnRegexp = regexp.MustCompile("^[a-zA-Z]+(\\.[a-zA-Z]+)*$") // only match string like `name`, `users.name`
 
+// quoteColumn quote column qualified with its table, current model's table is replaced with the one set with `Table`
+func (scope *Scope) quoteColumn(column Column) string {
+	if column.Table == "" {
+		return scope.Quote(column.Name)
+	}
+
+	tableName := column.Table
+	if scope.Search.tableName != "" && scope.Value != nil && scope.New(scope.Value).TableName() == tableName {
+		tableName = scope.Search.tableName
+	}
+	return scope.Quote(tableName) + "." + scope.Quote(column.Name)
+}
+
 func (scope *Scope) quoteIfPossible(str string) string {
 	if columnRegexp.MatchString(str) {
 		return scope.Quote(str)
@@ -508,13 +547,18 @@ func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str stri
 		if regexp.MustCompile("^\\s*\\d+\\s*$").MatchString(value) {
 			return scope.primaryCondition(scope.AddToVars(value))
 		} else if value != "" {
-			str = fmt.Sprintf("(%v)", value)
+			str = fmt.Sprintf("(%v)", scope.replaceRelationConditions(value))
 		}
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, sql.NullInt64:
 		return scope.primaryCondition(scope.AddToVars(value))
 	case []int, []int8, []int16, []int32, []int64, []uint, []uint8, []uint16, []uint32, []uint64, []string, []interface{}:
 		str = fmt.Sprintf("(%v.%v IN (?))", scope.QuotedTableName(), scope.Quote(scope.PrimaryKey()))
 		clause["args"] = []interface{}{value}
+	case *SQLExpr:
+		str = fmt.Sprintf("(%v)", value.expr)
+		clause["args"] = value.args
+	case *hasCondition:
+		return scope.buildHasCondition(value)
 	case map[string]interface{}:
 		var sqls []string
 		for key, value := range value {
@@ -562,6 +606,68 @@ func (scope *Scope) buildWhereCondition(clause map[string]interface{}) (str stri
 	return
 }
 
+// hasCondition condition added by `WhereHas`, rendered as `EXISTS` subquery of relation
+type hasCondition struct {
+	relation   string
+	conditions []interface{}
+}
+
+func (scope *Scope) buildHasCondition(condition *hasCondition) string {
+	field, ok := scope.GetModelStruct().relationField(condition.relation)
+	if !ok {
+		scope.Err(fmt.Errorf("can't find relation %s for %s", condition.relation, scope.GetModelStruct().ModelType))
+		return ""
+	}
+
+	var (
+		relation             = field.Relationship
+		relatedValue         = reflect.New(scope.relationModelStruct(field).ModelType).Interface()
+		relatedTableName     = scope.New(relatedValue).QuotedTableName()
+		quotedTableName      = scope.QuotedTableName()
+		subDB, subConditions = scope.generatePreloadDBWithConditions(condition.conditions)
+	)
+	subDB = subDB.Model(relatedValue)
+
+	switch relation.Kind {
+	case "has_one", "has_many":
+		for idx, foreignDBName := range relation.ForeignDBNames {
+			subDB = subDB.Where(fmt.Sprintf("%v.%v = %v.%v", relatedTableName, scope.Quote(foreignDBName), quotedTableName, scope.Quote(relation.AssociationForeignDBNames[idx])))
+		}
+
+		if relation.PolymorphicType != "" {
+			subDB = subDB.Where(fmt.Sprintf("%v.%v = ?", relatedTableName, scope.Quote(relation.PolymorphicDBName)), scope.TableName())
+		}
+	case "belongs_to":
+		for idx, foreignDBName := range relation.ForeignDBNames {
+			subDB = subDB.Where(fmt.Sprintf("%v.%v = %v.%v", relatedTableName, scope.Quote(relation.AssociationForeignDBNames[idx]), quotedTableName, scope.Quote(foreignDBName)))
+		}
+	case "many_to_many":
+		var (
+			joinTableName  = relation.JoinTableHandler.Table(scope.db)
+			joinConditions []string
+		)
+
+		for _, key := range relation.JoinTableHandler.DestinationForeignKeys() {
+			joinConditions = append(joinConditions, fmt.Sprintf("%v = %v.%v", scope.Quote(joinTableName+"."+key.DBName), relatedTableName, scope.Quote(key.AssociationDBName)))
+		}
+		subDB = subDB.Joins(fmt.Sprintf("INNER JOIN %v ON %v", scope.Quote(joinTableName), strings.Join(joinConditions, " AND ")))
+
+		for _, key := range relation.JoinTableHandler.SourceForeignKeys() {
+			subDB = subDB.Where(fmt.Sprintf("%v = %v.%v", scope.Quote(joinTableName+"."+key.DBName), quotedTableName, scope.Quote(key.AssociationDBName)))
+		}
+	}
+
+	if len(subConditions) > 0 {
+		subDB = subDB.Where(subConditions[0], subConditions[1:]...)
+	}
+
+	// build subquery with `?` placeholders, then add its values to current scope
+	subScope := subDB.NewScope(relatedValue)
+	subScope.skipBindVar = true
+	sql := fmt.Sprintf("EXISTS (SELECT 1 FROM %v %v%v)", subScope.QuotedTableName(), subScope.joinsSQL(), subScope.whereSQL())
+	return "(" + scope.AddToVars(Expr(sql, subScope.SQLVars...)) + ")"
+}
+
 func (scope *Scope) buildNotCondition(clause map[string]interface{}) (str string) {
 	var notEqualSQL string
 	var primaryKey = scope.PrimaryKey()
@@ -573,6 +679,7 @@ func (scope *Scope) buildNotCondition(clause map[string]interface{}) (str string
 			id, _ := strconv.Atoi(value)
 			return fmt.Sprintf("(%v <> %v)", scope.Quote(primaryKey), id)
 		} else if regexp.MustCompile("(?i) (=|<>|>|<|LIKE|IS|IN) ").MatchString(value) {
+			value = scope.replaceRelationConditions(value)
 			str = fmt.Sprintf(" NOT (%v) ", value)
 			notEqualSQL = fmt.Sprintf("NOT (%v)", value)
 		} else {
@@ -587,6 +694,8 @@ func (scope *Scope) buildNotCondition(clause map[string]interface{}) (str string
 			clause["args"] = []interface{}{value}
 		}
 		return ""
+	case *SQLExpr:
+		return "NOT " + scope.buildWhereCondition(map[string]interface{}{"query": value})
 	case map[string]interface{}:
 		var sqls []string
 		for key, value := range value {
@@ -639,6 +748,8 @@ func (scope *Scope) buildSelectQuery(clause map[string]interface{}) (str string)
 		str = value
 	case []string:
 		str = strings.Join(value, ", ")
+	case *SQLExpr:
+		str = scope.AddToVars(value)
 	}
 
 	args := clause["args"].([]interface{})
@@ -720,8 +831,19 @@ func (scope *Scope) whereSQL() (sql string) {
 
 func (scope *Scope) selectSQL() string {
 	if len(scope.Search.selects) == 0 {
-		if len(scope.Search.joinConditions) > 0 {
-			return fmt.Sprintf("%v.*", scope.QuotedTableName())
+		if len(scope.Search.joinConditions) > 0 || len(scope.referencedRelationFields()) > 0 {
+			columns := []string{fmt.Sprintf("%v.*", scope.QuotedTableName())}
+			for _, clause := range scope.Search.joinConditions {
+				if field := scope.joinedRelationField(clause); field != nil && (field.Relationship.Kind == "has_one" || field.Relationship.Kind == "belongs_to") {
+					alias := scope.Quote(field.Name)
+					for _, relationField := range scope.relationModelStruct(field).StructFields {
+						if relationField.IsNormal {
+							columns = append(columns, fmt.Sprintf("%v.%v AS %v", alias, scope.Quote(relationField.DBName), scope.Quote(scope.joinedColumnName(field, relationField))))
+						}
+					}
+				}
+			}
+			return strings.Join(columns, ", ")
 		}
 		return "*"
 	}
@@ -729,7 +851,27 @@ func (scope *Scope) selectSQL() string {
 }
 
 func (scope *Scope) orderSQL() string {
-	if len(scope.Search.orders) == 0 || scope.Search.countingQuery {
+	if scope.Search.countingQuery {
+		// orders are useless when counting, but some dbs like mssql still require one for OFFSET and FETCH NEXT
+		if scope.Dialect().Capabilities().OrderRequiredForOffset && scope.limitAndOffsetSQL() != "" {
+			return " ORDER BY (SELECT NULL)"
+		}
+		return ""
+	}
+
+	if len(scope.Search.orders) == 0 {
+		// some dbs like mssql require ORDER BY for OFFSET and FETCH NEXT, order by primary key by default
+		if scope.Dialect().Capabilities().OrderRequiredForOffset && scope.limitAndOffsetSQL() != "" {
+			var primaryKeys []string
+			for _, field := range scope.GetModelStruct().PrimaryFields {
+				primaryKeys = append(primaryKeys, fmt.Sprintf("%v.%v", scope.QuotedTableName(), scope.Quote(field.DBName)))
+			}
+
+			if len(primaryKeys) == 0 {
+				return " ORDER BY (SELECT NULL)"
+			}
+			return " ORDER BY " + strings.Join(primaryKeys, ",")
+		}
 		return ""
 	}
 
@@ -737,7 +879,7 @@ func (scope *Scope) orderSQL() string {
 	for _, order := range scope.Search.orders {
 		if str, ok := order.(string); ok {
 			orders = append(orders, scope.quoteIfPossible(str))
-		} else if expr, ok := order.(*expr); ok {
+		} else if expr, ok := order.(*SQLExpr); ok {
 			exp := expr.expr
 			for _, arg := range expr.args {
 				exp = strings.Replace(exp, "?", scope.AddToVars(arg), 1)
@@ -780,16 +922,222 @@ func (scope *Scope) havingSQL() string {
 }
 
 func (scope *Scope) joinsSQL() string {
-	var joinConditions []string
+	var (
+		joinConditions []string
+		joinedFields   = map[*StructField]bool{}
+	)
+
 	for _, clause := range scope.Search.joinConditions {
-		if sql := scope.buildWhereCondition(clause); sql != "" {
+		if field := scope.joinedRelationField(clause); field != nil {
+			if !joinedFields[field] {
+				joinedFields[field] = true
+				joinConditions = append(joinConditions, scope.relationJoinSQL(field, scope.QuotedTableName()))
+			}
+		} else if sql := scope.buildWhereCondition(clause); sql != "" {
 			joinConditions = append(joinConditions, strings.TrimSuffix(strings.TrimPrefix(sql, "("), ")"))
 		}
 	}
 
+	// relations referenced by conditions like `Company.Name = ?` are joined automatically,
+	// has many and many to many relations are queried with `EXISTS` subqueries instead so parents are not duplicated
+	for _, field := range scope.referencedRelationFields() {
+		if !joinedFields[field] && !scope.isQueriedWithExists(field) {
+			joinedFields[field] = true
+			joinConditions = append(joinConditions, scope.relationJoinSQL(field, scope.QuotedTableName()))
+		}
+	}
+
 	return strings.Join(joinConditions, " ") + " "
 }
 
+// joinedRelationField return relation field if join query is its name, e.g. `Joins("Profile")`
+func (scope *Scope) joinedRelationField(clause map[string]interface{}) *StructField {
+	name, ok := clause["query"].(string)
+	if !ok || name == "" || strings.ContainsAny(name, " \t\n()") {
+		return nil
+	}
+
+	if field, ok := scope.GetModelStruct().relationField(name); ok {
+		return field
+	}
+	return nil
+}
+
+// relationJoinSQL build LEFT JOIN of relation to parent table, which is quoted table name or alias,
+// related table is aliased with field name, join table of many to many relation isn't aliased
+func (scope *Scope) relationJoinSQL(field *StructField, quotedTableName string) string {
+	var (
+		relation      = field.Relationship
+		relationScope = scope.New(reflect.New(scope.relationModelStruct(field).ModelType).Interface())
+		alias         = scope.Quote(field.Name)
+		joinTableSQL  string
+		conditions    []string
+	)
+
+	switch relation.Kind {
+	case "has_one", "has_many":
+		for idx, foreignDBName := range relation.ForeignDBNames {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", alias, scope.Quote(foreignDBName), quotedTableName, scope.Quote(relation.AssociationForeignDBNames[idx])))
+		}
+
+		if relation.PolymorphicType != "" {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v", alias, scope.Quote(relation.PolymorphicDBName), scope.AddToVars(scope.TableName())))
+		}
+	case "belongs_to":
+		for idx, foreignDBName := range relation.ForeignDBNames {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", alias, scope.Quote(relation.AssociationForeignDBNames[idx]), quotedTableName, scope.Quote(foreignDBName)))
+		}
+	case "many_to_many":
+		var (
+			joinTableName       = relation.JoinTableHandler.Table(scope.db)
+			joinTableConditions []string
+		)
+
+		for _, key := range relation.JoinTableHandler.SourceForeignKeys() {
+			joinTableConditions = append(joinTableConditions, fmt.Sprintf("%v = %v.%v", scope.Quote(joinTableName+"."+key.DBName), quotedTableName, scope.Quote(key.AssociationDBName)))
+		}
+		joinTableSQL = fmt.Sprintf("LEFT JOIN %v ON %v ", scope.Quote(joinTableName), strings.Join(joinTableConditions, " AND "))
+
+		for _, key := range relation.JoinTableHandler.DestinationForeignKeys() {
+			conditions = append(conditions, fmt.Sprintf("%v.%v = %v", alias, scope.Quote(key.AssociationDBName), scope.Quote(joinTableName+"."+key.DBName)))
+		}
+	}
+
+	if relationScope.HasColumn("deleted_at") {
+		conditions = append(conditions, fmt.Sprintf("%v.%v IS NULL", alias, scope.Quote("deleted_at")))
+	}
+
+	return fmt.Sprintf("%vLEFT JOIN %v %v ON %v", joinTableSQL, relationScope.QuotedTableName(), alias, strings.Join(conditions, " AND "))
+}
+
+var relationReferenceRegexp = regexp.MustCompile(`\b([A-Z]\w*)\.([A-Za-z_]\w*)\b`)
+
+// replaceRelationReferences replace references of related fields like `Company.Name` with alias and column, e.g. `"Company"."name"`,
+// return relation fields that are referenced, string literals are not replaced
+func (scope *Scope) replaceRelationReferences(str string) (string, []*StructField) {
+	if !strings.Contains(str, ".") {
+		return str, nil
+	}
+
+	modelStruct := scope.GetModelStruct()
+	if !modelStruct.hasRelations() {
+		return str, nil
+	}
+
+	var (
+		fields []*StructField
+		result string
+		last   int
+	)
+
+	for _, match := range relationReferenceRegexp.FindAllStringSubmatchIndex(str, -1) {
+		if isInStringLiteral(str, match[0]) {
+			continue
+		}
+
+		field, ok := modelStruct.relationField(str[match[2]:match[3]])
+		if !ok {
+			continue
+		}
+
+		name := str[match[4]:match[5]]
+		for _, relationField := range scope.relationModelStruct(field).StructFields {
+			if relationField.IsNormal && (relationField.Name == name || relationField.DBName == name) {
+				result += str[last:match[0]] + scope.Quote(field.Name) + "." + scope.Quote(relationField.DBName)
+				last = match[1]
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+	return result + str[last:], fields
+}
+
+// isInStringLiteral check if position of str is in a quoted string literal like `'Foo.Bar'`
+func isInStringLiteral(str string, position int) bool {
+	return strings.Count(str[:position], "'")%2 == 1
+}
+
+// replaceRelationConditions replace references of related fields in condition,
+// condition is wrapped with `EXISTS` subquery if it references has many or many to many relations that are not joined with `Joins`
+func (scope *Scope) replaceRelationConditions(condition string) string {
+	condition, fields := scope.replaceRelationReferences(condition)
+
+	var existsFields []*StructField
+	for _, field := range fields {
+		if scope.isQueriedWithExists(field) {
+			existsFields = append(existsFields, field)
+		}
+	}
+
+	if len(existsFields) == 0 {
+		return condition
+	}
+	return scope.relationExistsSQL(existsFields, condition)
+}
+
+// isQueriedWithExists check if conditions of relation are queried with `EXISTS` subquery, joining has many or many to many relations
+// duplicates parents, so they are only joined if they are joined explicitly with `Joins` or parents don't have primary keys
+func (scope *Scope) isQueriedWithExists(field *StructField) bool {
+	if kind := field.Relationship.Kind; kind != "has_many" && kind != "many_to_many" {
+		return false
+	}
+
+	if len(scope.GetModelStruct().PrimaryFields) == 0 {
+		return false
+	}
+
+	for _, clause := range scope.Search.joinConditions {
+		if scope.joinedRelationField(clause) == field {
+			return false
+		}
+	}
+	return true
+}
+
+// relationExistsSQL build `EXISTS` subquery for condition that references relations, parent table is joined again as `gorm_parent`
+// by primary keys, so other columns in condition still refer to the parent
+func (scope *Scope) relationExistsSQL(fields []*StructField, condition string) string {
+	var (
+		parentAlias = scope.Quote("gorm_parent")
+		joinedField = map[*StructField]bool{}
+		joins       []string
+		conditions  []string
+	)
+
+	for _, field := range fields {
+		if !joinedField[field] {
+			joinedField[field] = true
+			joins = append(joins, scope.relationJoinSQL(field, parentAlias))
+		}
+	}
+
+	for _, field := range scope.GetModelStruct().PrimaryFields {
+		conditions = append(conditions, fmt.Sprintf("%v.%v = %v.%v", parentAlias, scope.Quote(field.DBName), scope.QuotedTableName(), scope.Quote(field.DBName)))
+	}
+
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %v %v %v WHERE %v AND (%v))",
+		scope.QuotedTableName(), parentAlias, strings.Join(joins, " "), strings.Join(conditions, " AND "), condition)
+}
+
+// referencedRelationFields relation fields referenced by string conditions
+func (scope *Scope) referencedRelationFields() (fields []*StructField) {
+	for _, conditions := range [][]map[string]interface{}{scope.Search.whereConditions, scope.Search.orConditions, scope.Search.notConditions} {
+		for _, clause := range conditions {
+			if str, ok := clause["query"].(string); ok {
+				_, referenced := scope.replaceRelationReferences(str)
+				fields = append(fields, referenced...)
+			}
+		}
+	}
+	return fields
+}
+
+// joinedColumnName alias of joined relation's column, e.g. `credit_card__number`
+func (scope *Scope) joinedColumnName(field *StructField, relationField *StructField) string {
+	return scope.namingStrategy().ColumnName(field.Name) + "__" + relationField.DBName
+}
+
 func (scope *Scope) prepareQuerySQL() {
 	if scope.Search.raw {
 		scope.Raw(strings.TrimSuffix(strings.TrimPrefix(scope.CombinedConditionSql(), " WHERE ("), ")"))
@@ -816,7 +1164,7 @@ func (scope *Scope) callCallbacks(funcs []*func(s *Scope)) *Scope {
 	return scope
 }
 
-func convertInterfaceToMap(values interface{}, withIgnoredField bool) map[string]interface{} {
+func (scope *Scope) convertInterfaceToMap(values interface{}, withIgnoredField bool) map[string]interface{} {
 	var attrs = map[string]interface{}{}
 
 	switch value := values.(type) {
@@ -824,7 +1172,7 @@ func convertInterfaceToMap(values interface{}, withIgnoredField bool) map[string
 		return value
 	case []interface{}:
 		for _, v := range value {
-			for key, value := range convertInterfaceToMap(v, withIgnoredField) {
+			for key, value := range scope.convertInterfaceToMap(v, withIgnoredField) {
 				attrs[key] = value
 			}
 		}
@@ -834,10 +1182,10 @@ func convertInterfaceToMap(values interface{}, withIgnoredField bool) map[string
 		switch reflectValue.Kind() {
 		case reflect.Map:
 			for _, key := range reflectValue.MapKeys() {
-				attrs[ToDBName(key.Interface().(string))] = reflectValue.MapIndex(key).Interface()
+				attrs[scope.namingStrategy().ColumnName(key.Interface().(string))] = reflectValue.MapIndex(key).Interface()
 			}
 		default:
-			for _, field := range (&Scope{Value: values}).Fields() {
+			for _, field := range scope.New(values).Fields() {
 				if !field.IsBlank && (withIgnoredField || !field.IsIgnored) {
 					attrs[field.DBName] = field.Field.Interface()
 				}
@@ -849,14 +1197,14 @@ func convertInterfaceToMap(values interface{}, withIgnoredField bool) map[string
 
 func (scope *Scope) updatedAttrsWithValues(value interface{}) (results map[string]interface{}, hasUpdate bool) {
 	if scope.IndirectValue().Kind() != reflect.Struct {
-		return convertInterfaceToMap(value, false), true
+		return scope.convertInterfaceToMap(value, false), true
 	}
 
 	results = map[string]interface{}{}
 
-	for key, value := range convertInterfaceToMap(value, true) {
+	for key, value := range scope.convertInterfaceToMap(value, true) {
 		if field, ok := scope.FieldByName(key); ok && scope.changeableField(field) {
-			if _, ok := value.(*expr); ok {
+			if _, ok := value.(*SQLExpr); ok {
 				hasUpdate = true
 				results[field.DBName] = value
 			} else {
@@ -898,7 +1246,7 @@ func (scope *Scope) initialize() *Scope {
 	return scope
 }
 
-func (scope *Scope) pluck(column string, value interface{}) *Scope {
+func (scope *Scope) pluck(column interface{}, value interface{}) *Scope {
 	dest := reflect.Indirect(reflect.ValueOf(value))
 	scope.Search.Select(column)
 	if dest.Kind() != reflect.Slice {
@@ -918,6 +1266,191 @@ func (scope *Scope) pluck(column string, value interface{}) *Scope {
 	return scope
 }
 
+// defaultIterateBatchSize how many records are preloaded together when iterating
+const defaultIterateBatchSize = 100
+
+func (scope *Scope) iterate(fc interface{}) *Scope {
+	fcValue := reflect.ValueOf(fc)
+	if fcType := reflect.TypeOf(fc); fcType == nil || fcType.Kind() != reflect.Func || fcType.NumIn() != 1 || fcType.In(0).Kind() != reflect.Ptr || fcType.In(0).Elem().Kind() != reflect.Struct ||
+		fcType.NumOut() != 1 || fcType.Out(0) != reflect.TypeOf((*error)(nil)).Elem() {
+		scope.Err(fmt.Errorf("iterate function should be func(*Model) error, but got %v", fcType))
+		return scope
+	}
+
+	elemType := fcValue.Type().In(0).Elem()
+	if scope.Value == nil {
+		scope.Value = reflect.New(elemType).Interface()
+	}
+
+	ctx := scope.db.getContext()
+
+	// a transaction has only one connection, which can't run queries of preloads, callbacks and fc while a result set is open,
+	// so records are read in batches, whose result sets are closed before they are processed
+	_, inTransaction := scope.db.db.(sqlTx)
+
+	batchSize := 1
+	if len(scope.Search.preload) > 0 || inTransaction {
+		batchSize = defaultIterateBatchSize
+		if size, ok := scope.Get("gorm:iterate_batch_size"); ok {
+			if size, ok := size.(int); ok && size > 0 {
+				batchSize = size
+			}
+		}
+	}
+
+	// preload associations and call callbacks for a batch of records, stop iterating if any error happened
+	process := func(batch reflect.Value) bool {
+		if batch.Len() == 0 {
+			return true
+		}
+
+		batchValue := reflect.New(batch.Type())
+		batchValue.Elem().Set(batch)
+		batchScope := scope.New(batchValue.Interface())
+		batchScope.Search.preload = scope.Search.preload
+		preloadCallback(batchScope)
+		if !batchScope.HasError() {
+			batchScope.CallMethod("AfterFind")
+		}
+
+		if scope.Err(batchScope.db.Error) != nil {
+			return false
+		}
+
+		for i := 0; i < batch.Len(); i++ {
+			if result := fcValue.Call([]reflect.Value{batch.Index(i)})[0]; !result.IsNil() {
+				scope.Err(result.Interface().(error))
+				return false
+			}
+		}
+		return true
+	}
+
+	scope.db.RowsAffected = 0
+	if inTransaction {
+		return scope.iterateByKeyset(elemType, batchSize, process)
+	}
+
+	rows, err := scope.rows()
+	if scope.Err(err) != nil {
+		return scope
+	}
+	defer rows.Close()
+
+	var (
+		columns, _ = rows.Columns()
+		scanner    = getScanPlan(scope.New(reflect.New(elemType).Interface()), columns).newScanner()
+		records    = reflect.New(reflect.SliceOf(reflect.PtrTo(elemType))).Elem()
+	)
+
+	for rows.Next() {
+		if ctx != nil && scope.Err(ctx.Err()) != nil {
+			return scope
+		}
+
+		record := reflect.New(elemType)
+		if scope.Err(scanner.scan(rows, record.Elem())) != nil {
+			return scope
+		}
+		scope.db.RowsAffected++
+
+		if records = reflect.Append(records, record); records.Len() >= batchSize {
+			if !process(records) {
+				return scope
+			}
+			records = records.Slice(0, 0)
+		}
+	}
+
+	if scope.Err(rows.Err()) == nil {
+		process(records)
+	}
+	return scope
+}
+
+// iterateByKeyset read batches of records ordered by current orders and primary keys, following batches are found with keyset conditions
+// like `Paginate`'s cursor, every batch is read completely and its result set is closed before it is processed
+func (scope *Scope) iterateByKeyset(elemType reflect.Type, batchSize int, process func(batch reflect.Value) bool) *Scope {
+	cursorColumns, err := scope.cursorColumns()
+	if err != nil {
+		scope.Err(fmt.Errorf("records are iterated in batches in a transaction, %v", err))
+		return scope
+	}
+
+	remaining := -1
+	if limit, err := strconv.Atoi(fmt.Sprint(scope.Search.limit)); err == nil && limit >= 0 {
+		remaining = limit
+	}
+
+	query := scope.db.clone()
+	query.search = scope.Search.clone()
+	query = query.Order(nil, true)
+	for _, column := range cursorColumns {
+		if column.desc {
+			query = query.Order(column.column + " DESC")
+		} else {
+			query = query.Order(column.column)
+		}
+	}
+
+	for remaining != 0 {
+		limit := batchSize
+		if remaining > 0 && remaining < limit {
+			limit = remaining
+		}
+
+		batchScope := query.Limit(limit).NewScope(scope.Value)
+		rows, err := batchScope.rows()
+		if scope.Err(err) != nil {
+			return scope
+		}
+
+		var (
+			columns, _ = rows.Columns()
+			scanner    = getScanPlan(scope.New(reflect.New(elemType).Interface()), columns).newScanner()
+			records    = reflect.New(reflect.SliceOf(reflect.PtrTo(elemType))).Elem()
+		)
+
+		for rows.Next() {
+			record := reflect.New(elemType)
+			if scope.Err(scanner.scan(rows, record.Elem())) != nil {
+				rows.Close()
+				return scope
+			}
+			records = reflect.Append(records, record)
+		}
+
+		if scope.Err(rows.Err()) != nil || scope.Err(rows.Close()) != nil {
+			return scope
+		}
+
+		if ctx := scope.db.getContext(); ctx != nil && scope.Err(ctx.Err()) != nil {
+			return scope
+		}
+
+		scope.db.RowsAffected += int64(records.Len())
+		if !process(records) || records.Len() < limit {
+			return scope
+		}
+
+		if remaining > 0 {
+			remaining -= records.Len()
+		}
+
+		cursor, err := encodeCursor(cursorColumns, scope.New(records.Index(records.Len()-1).Interface()))
+		if scope.Err(err) != nil {
+			return scope
+		}
+
+		sql, args, err := cursorCondition(cursorColumns, cursor)
+		if scope.Err(err) != nil {
+			return scope
+		}
+		query = query.Where(sql, args...).Offset(-1)
+	}
+	return scope
+}
+
 func (scope *Scope) count(value interface{}) *Scope {
 	if query, ok := scope.Search.selects["query"]; !ok || !regexp.MustCompile("(?i)^count(.+)$").MatchString(fmt.Sprint(query)) {
 		scope.Search.Select("count(*)")
@@ -1032,8 +1565,8 @@ func (scope *Scope) createJoinTable(field *StructField) {
 	if relationship := field.Relationship; relationship != nil && relationship.JoinTableHandler != nil {
 		joinTableHandler := relationship.JoinTableHandler
 		joinTable := joinTableHandler.Table(scope.db)
+		toScope := scope.New(reflect.New(field.Struct.Type).Interface())
 		if !scope.Dialect().HasTable(joinTable) {
-			toScope := &Scope{Value: reflect.New(field.Struct.Type).Interface()}
 
 			var sqlTypes, primaryKeys []string
 			for idx, fieldName := range relationship.ForeignFieldNames {
@@ -1058,19 +1591,77 @@ func (scope *Scope) createJoinTable(field *StructField) {
 				}
 			}
 
-			scope.Err(scope.NewDB().Exec(fmt.Sprintf("CREATE TABLE %v (%v, PRIMARY KEY (%v)) %s", scope.Quote(joinTable), strings.Join(sqlTypes, ","), strings.Join(primaryKeys, ","), scope.getTableOptions())).Error)
+			var constraints string
+			for _, foreignKey := range scope.joinTableForeignKeys(field, toScope) {
+				_, constraint := scope.foreignKeyConstraint(joinTable, foreignKey.DBNames, foreignKey.tableName, foreignKey.AssociationDBNames, foreignKey.OnDelete, foreignKey.OnUpdate)
+				constraints += ", " + constraint
+			}
+
+			scope.Err(scope.NewDB().Exec(fmt.Sprintf("CREATE TABLE %v (%v, PRIMARY KEY (%v)%v) %s", scope.Quote(joinTable), strings.Join(sqlTypes, ","), strings.Join(primaryKeys, ","), constraints, scope.getTableOptions())).Error)
+		} else {
+			for _, foreignKey := range scope.joinTableForeignKeys(field, toScope) {
+				scope.addForeignKeyConstraint(joinTable, foreignKey.DBNames, foreignKey.tableName, foreignKey.AssociationDBNames, foreignKey.OnDelete, foreignKey.OnUpdate)
+			}
 		}
 		scope.NewDB().Table(joinTable).AutoMigrate(joinTableHandler)
 	}
 }
 
+type joinTableForeignKey struct {
+	ForeignKey
+	tableName string
+}
+
+// joinTableForeignKeys return foreign keys from join table to source and destination tables if the many2many field has tag `constraint`
+func (scope *Scope) joinTableForeignKeys(field *StructField, toScope *Scope) (foreignKeys []joinTableForeignKey) {
+	foreignKey := newForeignKey(field, nil, nil, nil, nil)
+	if foreignKey == nil {
+		return
+	}
+
+	joinTableHandler := field.Relationship.JoinTableHandler
+	for _, keys := range []struct {
+		foreignKeys []JoinTableForeignKey
+		tableName   string
+	}{
+		{joinTableHandler.SourceForeignKeys(), scope.TableName()},
+		{joinTableHandler.DestinationForeignKeys(), toScope.TableName()},
+	} {
+		fk := joinTableForeignKey{ForeignKey: *foreignKey, tableName: keys.tableName}
+		for _, key := range keys.foreignKeys {
+			fk.DBNames = append(fk.DBNames, key.DBName)
+			fk.AssociationDBNames = append(fk.AssociationDBNames, key.AssociationDBName)
+		}
+		foreignKeys = append(foreignKeys, fk)
+	}
+	return
+}
+
+// dataTypeOf return column type of field, default values `true` and `false` of bool fields are written with boolean literals of the dialect
+func (scope *Scope) dataTypeOf(field *StructField) string {
+	fieldType := field.Struct.Type
+	for fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+	}
+
+	if value, ok := field.TagSettings["DEFAULT"]; ok && fieldType.Kind() == reflect.Bool {
+		capabilities := scope.Dialect().Capabilities()
+		literal := map[string]string{"TRUE": capabilities.TrueLiteral, "FALSE": capabilities.FalseLiteral}[strings.ToUpper(strings.TrimSpace(value))]
+		if literal != "" {
+			field = field.clone()
+			field.TagSettings["DEFAULT"] = literal
+		}
+	}
+	return scope.Dialect().DataTypeOf(field)
+}
+
 func (scope *Scope) createTable() *Scope {
 	var tags []string
 	var primaryKeys []string
 	var primaryKeyInColumnType = false
 	for _, field := range scope.GetModelStruct().StructFields {
 		if field.IsNormal {
-			sqlTag := scope.Dialect().DataTypeOf(field)
+			sqlTag := scope.dataTypeOf(field)
 
 			// Check if the primary key constraint was specified as
 			// part of the column type. If so, we can only support
@@ -1093,9 +1684,24 @@ func (scope *Scope) createTable() *Scope {
 		primaryKeyStr = fmt.Sprintf(", PRIMARY KEY (%v)", strings.Join(primaryKeys, ","))
 	}
 
+	for _, check := range scope.GetModelStruct().Checks {
+		primaryKeyStr += fmt.Sprintf(", CONSTRAINT %v CHECK (%v)", scope.quoteIfPossible(scope.checkName(check)), check.Constraint)
+	}
+
+	// referenced tables need to exist, skipped constraints will be added when migrating again
+	for _, foreignKey := range scope.tableForeignKeys() {
+		associationTableName := scope.New(reflect.New(foreignKey.AssociationType).Interface()).TableName()
+		if associationTableName == scope.TableName() || scope.Dialect().HasTable(associationTableName) {
+			_, constraint := scope.foreignKeyConstraint(scope.TableName(), foreignKey.DBNames, associationTableName, foreignKey.AssociationDBNames, foreignKey.OnDelete, foreignKey.OnUpdate)
+			primaryKeyStr += ", " + constraint
+		}
+	}
+
 	scope.Raw(fmt.Sprintf("CREATE TABLE %v (%v %v) %s", scope.QuotedTableName(), strings.Join(tags, ","), primaryKeyStr, scope.getTableOptions())).Exec()
 
 	scope.autoIndex()
+	scope.autoComment()
+	scope.autoAssociationForeignKeys()
 	return scope
 }
 
@@ -1105,7 +1711,16 @@ func (scope *Scope) dropTable() *Scope {
 }
 
 func (scope *Scope) modifyColumn(column string, typ string) {
-	scope.Raw(fmt.Sprintf("ALTER TABLE %v MODIFY %v %v", scope.QuotedTableName(), scope.Quote(column), typ)).Exec()
+	switch scope.Dialect().Capabilities().AlterColumn {
+	case AlterColumnModify:
+		scope.Raw(fmt.Sprintf("ALTER TABLE %v MODIFY %v %v", scope.QuotedTableName(), scope.Quote(column), typ)).Exec()
+	case AlterColumnType:
+		scope.Raw(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v TYPE %v", scope.QuotedTableName(), scope.Quote(column), typ)).Exec()
+	case AlterColumnPlain:
+		scope.Raw(fmt.Sprintf("ALTER TABLE %v ALTER COLUMN %v %v", scope.QuotedTableName(), scope.Quote(column), typ)).Exec()
+	default:
+		scope.Err(fmt.Errorf("%v doesn't support modifying columns", scope.Dialect().GetName()))
+	}
 }
 
 func (scope *Scope) dropColumn(column string) {
@@ -1113,6 +1728,11 @@ func (scope *Scope) dropColumn(column string) {
 }
 
 func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
+	scope.addIndexUsing(unique, indexName, "", column...)
+}
+
+// addIndexUsing add index with given index method, e.g. `btree`, `hash`, `gin`, which is only supported by postgres and mysql
+func (scope *Scope) addIndexUsing(unique bool, indexName string, method string, column ...string) {
 	if scope.Dialect().HasIndex(scope.TableName(), indexName) {
 		return
 	}
@@ -1127,11 +1747,102 @@ func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
 		sqlCreate = "CREATE UNIQUE INDEX"
 	}
 
-	scope.Raw(fmt.Sprintf("%s %v ON %v(%v) %v", sqlCreate, indexName, scope.QuotedTableName(), strings.Join(columns, ", "), scope.whereSQL())).Exec()
+	var usingSQL, usingSuffixSQL string
+	if method != "" {
+		switch scope.Dialect().Capabilities().IndexMethod {
+		case IndexMethodBeforeColumns:
+			usingSQL = " USING " + method
+		case IndexMethodAfterColumns:
+			usingSuffixSQL = " USING " + strings.ToUpper(method)
+		}
+	}
+
+	scope.Raw(fmt.Sprintf("%s %v ON %v%v(%v)%v %v", sqlCreate, indexName, scope.QuotedTableName(), usingSQL, strings.Join(columns, ", "), usingSuffixSQL, scope.whereSQL())).Exec()
+}
+
+// foreignKeyConstraint return foreign key's name and its constraint definition, which could be used in `CREATE TABLE` and `ALTER TABLE`
+func (scope *Scope) foreignKeyConstraint(tableName string, columns []string, associationTableName string, associationColumns []string, onDelete string, onUpdate string) (keyName string, constraint string) {
+	var quotedColumns, quotedAssociationColumns []string
+	for _, column := range columns {
+		quotedColumns = append(quotedColumns, scope.Quote(column))
+	}
+	for _, column := range associationColumns {
+		quotedAssociationColumns = append(quotedAssociationColumns, scope.Quote(column))
+	}
+
+	keyName = scope.foreignKeyName(tableName, strings.Join(columns, "_"), fmt.Sprintf("%v(%v)", associationTableName, strings.Join(associationColumns, ",")))
+	constraint = fmt.Sprintf("CONSTRAINT %v FOREIGN KEY (%v) REFERENCES %v(%v)", scope.quoteIfPossible(keyName), strings.Join(quotedColumns, ","), scope.Quote(associationTableName), strings.Join(quotedAssociationColumns, ","))
+	if onDelete != "" {
+		constraint += " ON DELETE " + onDelete
+	}
+	if onUpdate != "" {
+		constraint += " ON UPDATE " + onUpdate
+	}
+	return
+}
+
+// addForeignKeyConstraint add foreign key constraint to an existing table if it doesn't have it
+func (scope *Scope) addForeignKeyConstraint(tableName string, columns []string, associationTableName string, associationColumns []string, onDelete string, onUpdate string) {
+	// some dbs like sqlite can't add constraints to existing tables
+	if !scope.Dialect().Capabilities().AlterConstraints {
+		return
+	}
+
+	keyName, constraint := scope.foreignKeyConstraint(tableName, columns, associationTableName, associationColumns, onDelete, onUpdate)
+	if !scope.Dialect().HasForeignKey(tableName, keyName) {
+		scope.Err(scope.NewDB().Exec(fmt.Sprintf("ALTER TABLE %v ADD %v", scope.Quote(tableName), constraint)).Error)
+	}
+}
+
+// tableForeignKeys return foreign key constraints whose columns are in current model's table, they are defined by belongs to
+// relations of the model, or by has one, has many relations of other models migrated together with it
+func (scope *Scope) tableForeignKeys() (foreignKeys []*ForeignKey) {
+	modelType := scope.GetModelStruct().ModelType
+	for _, foreignKey := range scope.GetModelStruct().ForeignKeys {
+		if foreignKey.ModelType == modelType {
+			foreignKeys = append(foreignKeys, foreignKey)
+		}
+	}
+
+	if value, ok := scope.Get("gorm:association_foreign_keys"); ok {
+		for _, foreignKey := range value.([]*ForeignKey) {
+			if foreignKey.ModelType == modelType {
+				foreignKeys = append(foreignKeys, foreignKey)
+			}
+		}
+	}
+	return
+}
+
+// autoForeignKeys add foreign key constraints defined with tag `constraint` to an existing table
+func (scope *Scope) autoForeignKeys() *Scope {
+	tableName := scope.TableName()
+	for _, foreignKey := range scope.tableForeignKeys() {
+		if associationTableName := scope.New(reflect.New(foreignKey.AssociationType).Interface()).TableName(); scope.Dialect().HasTable(associationTableName) {
+			scope.addForeignKeyConstraint(tableName, foreignKey.DBNames, associationTableName, foreignKey.AssociationDBNames, foreignKey.OnDelete, foreignKey.OnUpdate)
+		}
+	}
+	return scope.autoAssociationForeignKeys()
+}
+
+// autoAssociationForeignKeys add foreign key constraints of has one, has many relations, they belong to the associated table,
+// so they are only added here if that table has been created before current one
+func (scope *Scope) autoAssociationForeignKeys() *Scope {
+	tableName := scope.TableName()
+	for _, foreignKey := range scope.GetModelStruct().ForeignKeys {
+		if foreignKey.ModelType == scope.GetModelStruct().ModelType {
+			continue
+		}
+
+		if toTableName := scope.New(reflect.New(foreignKey.ModelType).Interface()).TableName(); scope.Dialect().HasTable(toTableName) {
+			scope.addForeignKeyConstraint(toTableName, foreignKey.DBNames, tableName, foreignKey.AssociationDBNames, foreignKey.OnDelete, foreignKey.OnUpdate)
+		}
+	}
+	return scope
 }
 
 func (scope *Scope) addForeignKey(field string, dest string, onDelete string, onUpdate string) {
-	keyName := scope.Dialect().BuildForeignKeyName(scope.TableName(), field, dest)
+	keyName := scope.foreignKeyName(scope.TableName(), field, dest)
 
 	if scope.Dialect().HasForeignKey(scope.TableName(), keyName) {
 		return
@@ -1154,56 +1865,123 @@ func (scope *Scope) autoMigrate() *Scope {
 		for _, field := range scope.GetModelStruct().StructFields {
 			if !scope.Dialect().HasColumn(tableName, field.DBName) {
 				if field.IsNormal {
-					sqlTag := scope.Dialect().DataTypeOf(field)
+					sqlTag := scope.dataTypeOf(field)
 					scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD %v %v;", quotedTableName, scope.Quote(field.DBName), sqlTag)).Exec()
 				}
 			}
 			scope.createJoinTable(field)
 		}
 		scope.autoIndex()
+		scope.autoComment()
+		scope.autoCheck()
+		scope.autoForeignKeys()
 	}
 	return scope
 }
 
 func (scope *Scope) autoIndex() *Scope {
-	var indexes = map[string][]string{}
-	var uniqueIndexes = map[string][]string{}
+	for _, index := range scope.GetModelStruct().Indexes {
+		var (
+			name    = index.Name
+			columns []string
+		)
 
-	for _, field := range scope.GetStructFields() {
-		if name, ok := field.TagSettings["INDEX"]; ok {
-			names := strings.Split(name, ",")
+		for _, field := range index.Fields {
+			column := field.DBName
+			if field.Expression != "" {
+				column = "(" + field.Expression + ")"
+			}
+			if field.Sort != "" {
+				column += " " + field.Sort
+			}
+			columns = append(columns, column)
+		}
 
-			for _, name := range names {
-				if name == "INDEX" || name == "" {
-					name = fmt.Sprintf("idx_%v_%v", scope.TableName(), field.DBName)
-				}
-				indexes[name] = append(indexes[name], field.DBName)
+		if name == "" {
+			name = scope.namingStrategy().IndexName(scope.TableName(), index.Fields[0].DBName, index.Unique)
+		}
+
+		db := scope.NewDB().Model(scope.Value)
+		if index.Where != "" {
+			if !scope.Dialect().Capabilities().PartialIndexes {
+				scope.Err(fmt.Errorf("%v doesn't support partial index %v", scope.Dialect().GetName(), name))
+				continue
 			}
+			db = db.Where(index.Where)
 		}
+		db.Unscoped().NewScope(scope.Value).addIndexUsing(index.Unique, name, index.Using, columns...)
+	}
 
-		if name, ok := field.TagSettings["UNIQUE_INDEX"]; ok {
-			names := strings.Split(name, ",")
+	return scope
+}
 
-			for _, name := range names {
-				if name == "UNIQUE_INDEX" || name == "" {
-					name = fmt.Sprintf("uix_%v_%v", scope.TableName(), field.DBName)
-				}
-				uniqueIndexes[name] = append(uniqueIndexes[name], field.DBName)
+// autoComment set table comment returned by method `TableComment` and column comments defined with tag `comment`,
+// comments are only updated if they are changed, and ignored if the dialect doesn't support comments
+func (scope *Scope) autoComment() *Scope {
+	dialect, ok := scope.Dialect().(CommentDialect)
+	if !ok {
+		return scope
+	}
+
+	tableName := scope.TableName()
+	if commenter, ok := scope.Value.(tableCommenter); ok {
+		if comment := commenter.TableComment(); comment != dialect.Comment(tableName, "") {
+			scope.Err(scope.NewDB().Exec(dialect.CommentSQL(tableName, nil, comment)).Error)
+		}
+	}
+
+	for _, field := range scope.GetModelStruct().StructFields {
+		if comment, ok := field.TagSettings["COMMENT"]; ok && field.IsNormal {
+			if comment != dialect.Comment(tableName, field.DBName) {
+				scope.Err(scope.NewDB().Exec(dialect.CommentSQL(tableName, field, comment)).Error)
 			}
 		}
 	}
+	return scope
+}
+
+// checkName return check constraint's name, or the default one if it isn't named
+func (scope *Scope) checkName(check *Check) string {
+	if check.Name != "" {
+		return check.Name
+	}
+	return scope.namingStrategy().CheckName(scope.TableName(), check.Field.DBName)
+}
 
-	for name, columns := range indexes {
-		scope.NewDB().Model(scope.Value).AddIndex(name, columns...)
+// foreignKeyName return foreign key's name built by DB's naming strategy, or by dialect if the DB doesn't have one
+func (scope *Scope) foreignKeyName(tableName, columns, reference string) string {
+	if namingStrategy, _ := scope.db.customNaming(); namingStrategy != nil {
+		return namingStrategy.ForeignKeyName(tableName, columns, reference)
 	}
+	return scope.Dialect().BuildForeignKeyName(tableName, columns, reference)
+}
 
-	for name, columns := range uniqueIndexes {
-		scope.NewDB().Model(scope.Value).AddUniqueIndex(name, columns...)
+// autoCheck add check constraints defined with tag `check` to an existing table
+func (scope *Scope) autoCheck() *Scope {
+	// some dbs like sqlite can't add constraints to existing tables
+	if !scope.Dialect().Capabilities().AlterConstraints {
+		return scope
 	}
 
+	for _, check := range scope.GetModelStruct().Checks {
+		if name := scope.checkName(check); !scope.hasConstraint(scope.TableName(), name) {
+			scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD CONSTRAINT %v CHECK (%v)", scope.QuotedTableName(), scope.quoteIfPossible(name), check.Constraint)).Exec()
+		}
+	}
 	return scope
 }
 
+// hasConstraint check if table has constraint with `ConstraintDialect`, INFORMATION_SCHEMA is queried if the dialect doesn't implement it
+func (scope *Scope) hasConstraint(tableName string, constraintName string) bool {
+	if dialect, ok := scope.Dialect().(ConstraintDialect); ok {
+		return dialect.HasConstraint(tableName, constraintName)
+	}
+
+	var count int
+	scope.NewDB().Raw("SELECT count(*) FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE table_schema = ? AND table_name = ? AND constraint_name = ?", scope.Dialect().CurrentDatabase(), tableName, constraintName).Row().Scan(&count)
+	return count > 0
+}
+
 func (scope *Scope) getColumnAsArray(columns []string, values ...interface{}) (results [][]interface{}) {
 	for _, value := range values {
 		indirectValue := reflect.ValueOf(value)
@@ -15,6 +15,7 @@ type search struct {
 	omits            []string
 	orders           []interface{}
 	preload          []searchPreload
+	preloadCounts    []searchPreload
 	offset           interface{}
 	limit            interface{}
 	group            string
@@ -117,6 +118,17 @@ func (s *search) Preload(schema string, values ...interface{}) *search {
 	return s
 }
 
+func (s *search) PreloadCount(schema string, values ...interface{}) *search {
+	var preloads []searchPreload
+	for _, preload := range s.preloadCounts {
+		if preload.schema != schema {
+			preloads = append(preloads, preload)
+		}
+	}
+	s.preloadCounts = append(preloads, searchPreload{schema, values})
+	return s
+}
+
 func (s *search) Raw(b bool) *search {
 	s.raw = b
 	return s
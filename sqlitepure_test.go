@@ -0,0 +1,7 @@
+//go:build sqlitepure
+// +build sqlitepure
+
+package gorm_test
+
+// run tests with pure Go sqlite driver: GORM_DIALECT=sqlite-pure go test -tags sqlitepure
+import _ "github.com/nkovacs/gorm/dialects/sqlitepure"
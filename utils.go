@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // NowFunc returns current time, this function is exported in order to be able
@@ -110,16 +112,55 @@ func ToDBName(name string) string {
 	return s
 }
 
-// SQL expression
-type expr struct {
+// ToFieldName convert db name to struct field name, it is the reverse of `ToDBName`, characters other than letters and digits
+// are treated as separators, and the name is prefixed with `Column` if it starts with a digit, for example:
+//     ToFieldName("http_server_url_id") // HTTPServerURLID
+//     ToFieldName("2fa code")           // Column2faCode
+func ToFieldName(name string) string {
+	var buf bytes.Buffer
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		upperPart := strings.ToUpper(part)
+		isInitialism := false
+		for _, initialism := range commonInitialisms {
+			if initialism == upperPart {
+				isInitialism = true
+				break
+			}
+		}
+
+		if isInitialism {
+			buf.WriteString(upperPart)
+		} else {
+			first, size := utf8.DecodeRuneInString(part)
+			buf.WriteRune(unicode.ToUpper(first))
+			buf.WriteString(part[size:])
+		}
+	}
+
+	if first, _ := utf8.DecodeRune(buf.Bytes()); unicode.IsDigit(first) {
+		return "Column" + buf.String()
+	}
+	return buf.String()
+}
+
+// SQLExpr SQL expression created with `Expr`, could be used as query conditions, orders and selected columns
+type SQLExpr struct {
 	expr string
 	args []interface{}
 }
 
 // Expr generate raw SQL expression, for example:
 //     DB.Model(&product).Update("price", gorm.Expr("price * ? + ?", 2, 100))
-func Expr(expression string, args ...interface{}) *expr {
-	return &expr{expr: expression, args: args}
+func Expr(expression string, args ...interface{}) *SQLExpr {
+	return &SQLExpr{expr: expression, args: args}
+}
+
+// Column column of table used as argument of expressions, it is quoted with the dialect when building SQL instead of being a bind var,
+// the table is replaced with the one set with `Table` if it is the table of current model, for example:
+//     DB.Where(gorm.Expr("? > ?", gorm.Column{Table: "products", Name: "price"}, 10)).Find(&products)
+type Column struct {
+	Table string
+	Name  string
 }
 
 func indirect(reflectValue reflect.Value) reflect.Value {
@@ -129,6 +170,13 @@ func indirect(reflectValue reflect.Value) reflect.Value {
 	return reflectValue
 }
 
+func indirectType(reflectType reflect.Type) reflect.Type {
+	for reflectType.Kind() == reflect.Ptr {
+		reflectType = reflectType.Elem()
+	}
+	return reflectType
+}
+
 func toQueryMarks(primaryValues [][]interface{}) string {
 	var results []string
 
@@ -28,3 +28,24 @@ func TestToDBNameGenerateFriendlyName(t *testing.T) {
 		}
 	}
 }
+
+func TestToFieldName(t *testing.T) {
+	var maps = map[string]string{
+		"":                               "",
+		"this_is_a_test":                 "ThisIsATest",
+		"employee_id":                    "EmployeeID",
+		"http_server_handler_for_url_id": "HTTPServerHandlerForURLID",
+		"uuid":                           "UUID",
+		"_leading__underscores":          "LeadingUnderscores",
+		"first name":                     "FirstName",
+		"zip-code":                       "ZipCode",
+		"2fa_code":                       "Column2faCode",
+		"ár":                             "Ár",
+	}
+
+	for key, value := range maps {
+		if gorm.ToFieldName(key) != value {
+			t.Errorf("%v ToFieldName should equal %v, but got %v", key, value, gorm.ToFieldName(key))
+		}
+	}
+}